@@ -0,0 +1,136 @@
+package openpomodoro
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// BatchAction is what a BatchOp does.
+type BatchAction string
+
+const (
+	// BatchStart starts a Pomodoro, cancelling one started before it by the
+	// batch if it is still running.
+	BatchStart BatchAction = "start"
+
+	// BatchFinish finishes the running Pomodoro.
+	BatchFinish BatchAction = "finish"
+
+	// BatchCancel cancels the running Pomodoro.
+	BatchCancel BatchAction = "cancel"
+)
+
+// BatchOp is a single start, finish, or cancel applied by ApplyBatch.
+type BatchOp struct {
+	Action BatchAction
+	Time   time.Time
+
+	// Pomodoro is the Pomodoro to start, which may be nil for BatchStart to
+	// use defaults. It is ignored by other actions.
+	Pomodoro *Pomodoro
+}
+
+// ApplyBatch applies the operations in time order to the `history` file, as
+// if each had been done with Start, Finish, or Cancel at its time, and
+// rewrites the file once. Either all of them are applied, or an error is
+// returned and nothing is changed. The `current` file is not used, so a
+// Pomodoro which is started but not finished is recorded with its planned
+// duration, like Log. It returns ErrNotActive for a finish or cancel with
+// nothing running, ErrOverlap if a Pomodoro would overlap one already in the
+// history, and the errors of Validate for an invalid duration.
+func (c *Client) ApplyBatch(ops []BatchOp) error {
+	s, err := c.Settings()
+	if err != nil {
+		return err
+	}
+
+	history, err := c.History()
+	if err != nil {
+		return err
+	}
+
+	ops = append([]BatchOp{}, ops...)
+	sort.SliceStable(ops, func(i, j int) bool {
+		return ops[i].Time.Before(ops[j].Time)
+	})
+
+	var added []*Pomodoro
+	var running *Pomodoro
+
+	cancel := func(t time.Time) {
+		if s.KeepCancelled {
+			running.setActualDuration(t.Sub(running.StartTime))
+			running.Cancelled = true
+			return
+		}
+
+		for i, p := range added {
+			if p == running {
+				added = append(added[:i], added[i+1:]...)
+				break
+			}
+		}
+	}
+
+	for _, op := range ops {
+		switch op.Action {
+		case BatchStart:
+			p := &Pomodoro{}
+			if op.Pomodoro != nil {
+				p = op.Pomodoro.Copy()
+			}
+			p.StartTime = op.Time
+			p.ApplySettings(s)
+
+			if err := p.Validate(s); err != nil {
+				return err
+			}
+
+			if running != nil && op.Time.Before(running.EndTime()) {
+				cancel(op.Time)
+			}
+
+			added = append(added, p)
+			running = p
+		case BatchFinish, BatchCancel:
+			if running == nil {
+				return ErrNotActive
+			}
+
+			if op.Action == BatchCancel {
+				cancel(op.Time)
+			} else {
+				elapsed := op.Time.Sub(running.StartTime)
+				if s.RoundFinishTo > 0 {
+					elapsed = elapsed.Round(s.RoundFinishTo)
+				}
+				running.setActualDuration(elapsed)
+			}
+
+			running = nil
+		default:
+			return fmt.Errorf("unknown batch action %q", op.Action)
+		}
+	}
+
+	for _, p := range added {
+		for _, existing := range history.Pomodoros {
+			if p.Overlaps(existing) {
+				return ErrOverlap
+			}
+		}
+	}
+
+	if len(added) == 0 {
+		return nil
+	}
+
+	if err := c.ensureDirectory(); err != nil {
+		return err
+	}
+
+	history.Pomodoros = append(history.Pomodoros, added...)
+
+	return c.writeHistory(history)
+}
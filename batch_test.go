@@ -0,0 +1,122 @@
+package openpomodoro
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ApplyBatch(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	day := time.Date(2016, 06, 13, 9, 0, 0, 0, time.UTC)
+	require.Nil(t, c.ApplyBatch([]BatchOp{
+		{Action: BatchFinish, Time: day.Add(20 * time.Minute)},
+		{Action: BatchStart, Time: day, Pomodoro: &Pomodoro{Description: "first"}},
+		{Action: BatchStart, Time: day.Add(30 * time.Minute), Pomodoro: &Pomodoro{Description: "cancelled"}},
+		{Action: BatchStart, Time: day.Add(40 * time.Minute), Pomodoro: &Pomodoro{Description: "replacing"}},
+		{Action: BatchStart, Time: day.Add(2 * time.Hour), Pomodoro: &Pomodoro{Description: "unfinished"}},
+	}))
+
+	h, err := c.History()
+	require.Nil(t, err)
+	require.Equal(t, 3, h.Count())
+
+	assert.Equal(t, "first", h.Pomodoros[0].Description)
+	assert.Equal(t, 20*time.Minute, h.Pomodoros[0].Duration)
+	assert.Equal(t, 25*time.Minute, h.Pomodoros[0].Planned())
+
+	assert.Equal(t, "replacing", h.Pomodoros[1].Description)
+	assert.Equal(t, 25*time.Minute, h.Pomodoros[1].Duration)
+
+	assert.Equal(t, "unfinished", h.Pomodoros[2].Description)
+	assert.Equal(t, 25*time.Minute, h.Pomodoros[2].Duration)
+
+	current, err := c.Pomodoro()
+	require.Nil(t, err)
+	assert.True(t, current.IsInactive())
+}
+
+func TestClient_ApplyBatch_allOrNothing(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	day := time.Date(2016, 06, 13, 9, 0, 0, 0, time.UTC)
+	require.Nil(t, c.Log(&Pomodoro{}, day.Add(time.Hour), 25*time.Minute))
+
+	assert.Equal(t, ErrNotActive, c.ApplyBatch([]BatchOp{
+		{Action: BatchStart, Time: day},
+		{Action: BatchFinish, Time: day.Add(20 * time.Minute)},
+		{Action: BatchCancel, Time: day.Add(30 * time.Minute)},
+	}))
+
+	assert.Equal(t, ErrOverlap, c.ApplyBatch([]BatchOp{
+		{Action: BatchStart, Time: day},
+		{Action: BatchStart, Time: day.Add(50 * time.Minute)},
+	}))
+
+	assert.Equal(t, ErrInvalidDuration, c.ApplyBatch([]BatchOp{
+		{Action: BatchStart, Time: day},
+		{Action: BatchStart, Time: day.Add(30 * time.Minute), Pomodoro: &Pomodoro{Duration: -time.Minute}},
+	}))
+
+	assert.NotNil(t, c.ApplyBatch([]BatchOp{{Action: "pause", Time: day}}))
+
+	assertHistoryLength(1)(t, c, "")
+}
+
+func TestClient_ApplyBatch_writeError(t *testing.T) {
+	defer func() { renameFile = os.Rename }()
+
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	day := time.Date(2016, 06, 13, 9, 0, 0, 0, time.UTC)
+	require.Nil(t, c.Log(&Pomodoro{}, day.Add(time.Hour), 25*time.Minute))
+
+	before, err := ioutil.ReadFile(c.HistoryPath())
+	require.Nil(t, err)
+
+	failure := errors.New("failure")
+	renameFile = func(string, string) error { return failure }
+
+	assert.Equal(t, failure, c.ApplyBatch([]BatchOp{
+		{Action: BatchStart, Time: day},
+		{Action: BatchFinish, Time: day.Add(20 * time.Minute)},
+	}))
+
+	after, err := ioutil.ReadFile(c.HistoryPath())
+	require.Nil(t, err)
+	assert.Equal(t, before, after)
+
+	files, err := ioutil.ReadDir(c.Directory)
+	require.Nil(t, err)
+	for _, f := range files {
+		assert.False(t, strings.HasPrefix(f.Name(), "."), f.Name())
+	}
+}
+
+func TestClient_ApplyBatch_finishImmediately(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	day := time.Date(2016, 06, 13, 9, 0, 0, 0, time.UTC)
+	require.Nil(t, c.ApplyBatch([]BatchOp{
+		{Action: BatchStart, Time: day},
+		{Action: BatchFinish, Time: day},
+	}))
+
+	h, err := c.History()
+	require.Nil(t, err)
+	require.Equal(t, 1, h.Count())
+	assert.Equal(t, time.Duration(0), h.Latest().Duration)
+	assert.Equal(t, 25*time.Minute, h.Latest().Planned())
+	assert.Equal(t, time.Duration(0), h.Duration())
+}
@@ -0,0 +1,53 @@
+package openpomodoro
+
+import (
+	"bytes"
+	"sort"
+	"time"
+)
+
+// Break records a break starting now in the `breaks` file, so that
+// SincePreviousBreak counts from it.
+func (c *Client) Break() error {
+	return c.BreakAt(c.now())
+}
+
+// BreakAt records a break which started at t in the `breaks` file.
+func (c *Client) BreakAt(t time.Time) error {
+	if err := c.ensureDirectory(); err != nil {
+		return err
+	}
+
+	line := append([]byte(t.Format(TimeFormat)), charNewline...)
+	return c.appendFile(c.BreaksPath(), line)
+}
+
+// Breaks returns the start times of the breaks in the `breaks` file, in
+// ascending order. Lines which cannot be parsed are skipped.
+func (c *Client) Breaks() ([]time.Time, error) {
+	b, err := readRaw(c.BreaksPath())
+	if err != nil {
+		return nil, err
+	}
+
+	breaks := []time.Time{}
+	for _, line := range bytes.Split(b, charNewline) {
+		if bytesAllWhitespace(line) {
+			continue
+		}
+
+		t, _, err := parseTimestamp(bytes.TrimSpace(line), nil)
+		if err != nil {
+			debug("skipping breaks line %q: %s", line, err)
+			continue
+		}
+
+		breaks = append(breaks, t)
+	}
+
+	sort.Slice(breaks, func(i, j int) bool {
+		return breaks[i].Before(breaks[j])
+	})
+
+	return breaks, nil
+}
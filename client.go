@@ -1,23 +1,111 @@
 package openpomodoro
 
 import (
+	"bufio"
 	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"os/user"
 	"path"
 	"path/filepath"
+	"runtime"
 	"sort"
+	"time"
+
+	"gopkg.in/yaml.v2"
 )
 
 // Client holds the location of the directory and files.
+//
+// The CurrentFile, HistoryFile, and SettingsFile fields are deprecated for
+// reading; use the CurrentPath, HistoryPath, and SettingsPath methods instead.
 type Client struct {
 	Directory    string
 	CurrentFile  string
 	HistoryFile  string
 	SettingsFile string
+
+	// DryRun prevents the Client from writing any files. The writes which
+	// would have been made are recorded in DryRunWrites instead.
+	DryRun       bool
+	DryRunWrites []Write
+
+	// ReadOnly makes every method which would change a file return
+	// ErrReadOnly without changing anything, while reads work normally.
+	ReadOnly bool
+
+	// TodayCache makes Today keep its result in the `today` file.
+	TodayCache bool
+
+	// DisableHistory keeps the timer in the `current` file only, so that
+	// starting, finishing, cancelling, or changing the current Pomodoro never
+	// reads or writes the `history` file. Methods which are only about the
+	// history, such as Log and Import, still change it.
+	DisableHistory bool
+
+	// Notifier, if set, is notified of lifecycle events.
+	Notifier Notifier
+
+	// HistoryOrder is the order of Pomodoros in the `history` file, which is
+	// Ascending if empty. History always returns them in ascending order.
+	HistoryOrder HistoryOrder
+
+	// DirPerm and FilePerm are the permissions set when creating directories
+	// and files, which are the DirPerm and FilePerm constants if zero.
+	DirPerm  os.FileMode
+	FilePerm os.FileMode
+
+	// undoFile is the location of the `undo` file.
+	undoFile string
+
+	// journalFile is the location of the `journal` file.
+	journalFile string
+
+	// versionFile is the location of the `version` file.
+	versionFile string
+
+	// scheduleFile is the location of the `schedule` file.
+	scheduleFile string
+
+	// todayFile is the location of the `today` file.
+	todayFile string
+
+	// breaksFile is the location of the `breaks` file.
+	breaksFile string
+
+	// Clock is where the Client gets the current time, which is the system time
+	// if nil. It is passed on to the Pomodoros the Client reads and starts.
+	Clock Clock
+
+	// OnNotifyError, if set, is called when the Notifier returns an error.
+	// Otherwise the error is only logged when debugging.
+	OnNotifyError func(event string, p *Pomodoro, err error)
 }
 
+// Write is a change to a file which was skipped because of DryRun.
+type Write struct {
+	File   string
+	Data   []byte
+	Append bool
+	Remove bool
+}
+
+// HistoryOrder is the order of Pomodoros in the `history` file.
+type HistoryOrder string
+
+const (
+	// Ascending stores the oldest Pomodoro first, and appends new ones.
+	Ascending HistoryOrder = "asc"
+
+	// Descending stores the newest Pomodoro first, which means the whole
+	// file is rewritten when a Pomodoro is added.
+	Descending HistoryOrder = "desc"
+)
+
 // State is a collection of all state.
 type State struct {
 	Pomodoro *Pomodoro
@@ -25,24 +113,54 @@ type State struct {
 	Settings *Settings
 }
 
+// ErrAlreadyActive is returned by StartStrict when a Pomodoro is already
+// active.
+var ErrAlreadyActive = errors.New("a Pomodoro is already active")
+
+// ErrOverlap is returned by Log when a Pomodoro would overlap an existing one
+// in the history.
+var ErrOverlap = errors.New("Pomodoro overlaps an existing one")
+
+// ErrInvalidDuration is returned by Start and Log when the duration is not
+// positive.
+var ErrInvalidDuration = errors.New("duration must be positive")
+
+// ErrTruncatedHistory is added to the warnings of a History when the last line
+// of the `history` file was incomplete, such as after a crash while writing
+// it, and was dropped.
+var ErrTruncatedHistory = errors.New("incomplete last line of history was dropped")
+
+// ErrReadOnly is returned by methods which would change a file when the Client
+// is ReadOnly.
+var ErrReadOnly = errors.New("client is read-only")
+
+var (
+	userCurrent = user.Current
+	renameFile  = os.Rename
+)
+
 const (
-	// FilePerm are the permissions set when creating files.
+	// DirPerm are the default permissions set when creating directories.
+	DirPerm = 0755
+
+	// FilePerm are the default permissions set when creating files.
 	FilePerm = 0644
 )
 
 // NewClient returns a new Client with the given directory. If the directory is
-// an empty string, the default directory of ~/.pomodoro is used.
-func NewClient(directory string) (*Client, error) {
+// an empty string, the default directory for the platform is used, which is
+// ~/.pomodoro on Unix and %APPDATA%\pomodoro on Windows, unless
+// %USERPROFILE%\.pomodoro already exists. Options may be given to change the
+// location of individual files.
+func NewClient(directory string, options ...Option) (*Client, error) {
 	var d string
-	var u *user.User
 	var err error
 
 	if directory == "" {
-		u, err = user.Current()
+		d, err = defaultDirectory()
 		if err != nil {
 			return nil, err
 		}
-		d = path.Join(u.HomeDir, ".pomodoro")
 	} else {
 		d, err = filepath.Abs(directory)
 		if err != nil {
@@ -55,11 +173,66 @@ func NewClient(directory string) (*Client, error) {
 		CurrentFile:  path.Join(d, "current"),
 		HistoryFile:  path.Join(d, "history"),
 		SettingsFile: path.Join(d, "settings"),
+		undoFile:     path.Join(d, "undo"),
+		journalFile:  path.Join(d, "journal"),
+		versionFile:  path.Join(d, "version"),
+		scheduleFile: path.Join(d, "schedule"),
+		todayFile:    path.Join(d, "today"),
+		breaksFile:   path.Join(d, "breaks"),
+	}
+
+	for _, option := range options {
+		option(c)
 	}
 
 	return c, nil
 }
 
+// CurrentPath returns the path of the `current` file.
+func (c *Client) CurrentPath() string {
+	return c.CurrentFile
+}
+
+// HistoryPath returns the path of the `history` file.
+func (c *Client) HistoryPath() string {
+	return c.HistoryFile
+}
+
+// SettingsPath returns the path of the `settings` file.
+func (c *Client) SettingsPath() string {
+	return c.SettingsFile
+}
+
+// UndoPath returns the path of the `undo` file.
+func (c *Client) UndoPath() string {
+	return c.undoFile
+}
+
+// JournalPath returns the path of the `journal` file.
+func (c *Client) JournalPath() string {
+	return c.journalFile
+}
+
+// VersionPath returns the path of the `version` file.
+func (c *Client) VersionPath() string {
+	return c.versionFile
+}
+
+// SchedulePath returns the path of the `schedule` file.
+func (c *Client) SchedulePath() string {
+	return c.scheduleFile
+}
+
+// TodayPath returns the path of the `today` file.
+func (c *Client) TodayPath() string {
+	return c.todayFile
+}
+
+// BreaksPath returns the path of the `breaks` file.
+func (c *Client) BreaksPath() string {
+	return c.breaksFile
+}
+
 // CurrentState returns a State with the current Pomodoro, history, and
 // settings.
 func (c *Client) CurrentState() (*State, error) {
@@ -86,53 +259,313 @@ func (c *Client) CurrentState() (*State, error) {
 	return state, nil
 }
 
-// History returns all Pomodoros from the `history` file.
+// Snapshot returns a State like CurrentState, except that everything is deeply
+// copied, so it is safe to share without worrying about later mutations.
+func (c *Client) Snapshot() (*State, error) {
+	state, err := c.CurrentState()
+	if err != nil {
+		return nil, err
+	}
+
+	return &State{
+		Pomodoro: state.Pomodoro.Copy(),
+		History:  state.History.Copy(),
+		Settings: state.Settings.Copy(),
+	}, nil
+}
+
+// History returns all Pomodoros from the `history` file, with the rounding and
+// day start offset from settings, oldest first regardless of the HistoryOrder.
+// If the last line is incomplete and cannot be read, it is dropped and
+// ErrTruncatedHistory is added to the warnings.
 func (c *Client) History() (*History, error) {
-	ps := []*Pomodoro{}
+	s, err := c.readSettings()
+	if err != nil {
+		return nil, err
+	}
 
-	b, err := ioutil.ReadFile(c.HistoryFile)
+	b, err := ioutil.ReadFile(c.HistoryPath())
 	if err != nil {
-		if os.IsNotExist(err) {
-			return &History{Pomodoros: ps}, nil
+		if !os.IsNotExist(err) {
+			return nil, err
 		}
-		return nil, err
 	}
 
+	h := parseHistory(b, s, c.Clock)
+	if c.HistoryOrder == Descending {
+		h.reverse()
+	}
+
+	return h, nil
+}
+
+// parseHistory parses the contents of a `history` file, with the rounding, tag
+// separator, and day start offset from settings, and the clock.
+func parseHistory(b []byte, s *Settings, clock Clock) *History {
+	ps := []*Pomodoro{}
+	h := &History{DayStartOffset: s.DayStartOffset}
 	lines := bytes.Split(b, charNewline)
 
-	for _, line := range lines {
+	for i, line := range lines {
 		if bytesAllWhitespace(line) {
 			continue
 		}
 
 		p := NewPomodoro()
-		p.UnmarshalText(line)
+		p.Rounding = s.Rounding
+		p.TagSeparator = s.TagSeparator
+		p.Clock = clock
+		err := p.UnmarshalText(line)
+		if err != nil && i == len(lines)-1 {
+			debug("dropping incomplete history line %q: %s", line, err)
+			h.Warnings = append(h.Warnings, ErrTruncatedHistory)
+			continue
+		}
 		ps = append(ps, p)
 	}
 
-	return &History{Pomodoros: ps}, nil
+	h.Pomodoros = ps
+	return h
+}
+
+// CompletedHistory returns the completed Pomodoros from the `history` file.
+func (c *Client) CompletedHistory() (*History, error) {
+	h, err := c.History()
+	if err != nil {
+		return nil, err
+	}
+
+	return h.Completed(), nil
+}
+
+// AbandonedHistory returns the Pomodoros from the `history` file which were not
+// completed, such as those kept after being cancelled.
+func (c *Client) AbandonedHistory() (*History, error) {
+	h, err := c.History()
+	if err != nil {
+		return nil, err
+	}
+
+	return h.Abandoned(), nil
+}
+
+// CountSince returns the number of Pomodoros in the `history` file started at or
+// after the given time.
+func (c *Client) CountSince(t time.Time) (int, error) {
+	h, err := c.History()
+	if err != nil {
+		return 0, err
+	}
+
+	return h.Since(t).Count(), nil
+}
+
+// DurationSince returns the total duration of Pomodoros in the `history` file
+// started at or after the given time.
+func (c *Client) DurationSince(t time.Time) (time.Duration, error) {
+	h, err := c.History()
+	if err != nil {
+		return 0, err
+	}
+
+	return h.Since(t).Duration(), nil
+}
+
+// RemainingToGoal returns how many more Pomodoros of the default duration, and
+// how much time, are needed to reach the daily goal on the day containing now.
+// Only Pomodoros completed by now are counted, so one which is running counts
+// as remaining. Both are zero if the goal is met or not set, or if the day is
+// not a work day.
+func (c *Client) RemainingToGoal(now time.Time) (pomodoros int, duration time.Duration, err error) {
+	s, err := c.Settings()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if s.DailyGoal <= 0 || !s.IsWorkDay(s.Day(now)) {
+		return 0, 0, nil
+	}
+
+	h, err := c.History()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	completed := h.completedOnDay(now)
+	if completed >= s.DailyGoal {
+		return 0, 0, nil
+	}
+
+	pomodoros = s.DailyGoal - completed
+	return pomodoros, time.Duration(pomodoros) * s.DefaultPomodoroDuration, nil
+}
+
+// GoalPace returns whether or not enough Pomodoros have been completed on the
+// day containing now to be on track for the daily goal, along with how many
+// would be expected by now and how many were completed. The expected number is
+// the goal in proportion to how much of the workday window has elapsed, rounded
+// down. It is always on track if no goal is set, or if the day is not a work
+// day, where none are expected.
+func (c *Client) GoalPace(now time.Time) (onTrack bool, expected int, actual int, err error) {
+	s, err := c.Settings()
+	if err != nil {
+		return false, 0, 0, err
+	}
+
+	h, err := c.History()
+	if err != nil {
+		return false, 0, 0, err
+	}
+
+	actual = h.completedOnDay(now)
+	if s.IsWorkDay(s.Day(now)) {
+		expected = int(float64(s.DailyGoal) * s.WorkdayElapsed(now))
+	}
+
+	return actual >= expected, expected, actual, nil
+}
+
+// SincePreviousBreak returns how many Pomodoros were completed, and their total
+// duration, since the most recent break recorded by Break or BreakAt before
+// now, or since the start of the day containing now if there is none. A
+// Pomodoro which is still running is not counted.
+func (c *Client) SincePreviousBreak(now time.Time) (pomodoros int, duration time.Duration, err error) {
+	h, err := c.History()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	breaks, err := c.Breaks()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	since := h.StartOfDay(now)
+	for _, t := range breaks {
+		if t.After(since) && !t.After(now) {
+			since = t
+		}
+	}
+
+	for _, p := range h.Pomodoros {
+		if !p.IsCompleted() || p.StartTime.Before(since) || p.EndTime().After(now) {
+			continue
+		}
+
+		pomodoros++
+		duration += p.Duration
+	}
+
+	return pomodoros, duration, nil
 }
 
-// Pomodoro returns the current Pomodoro from the `current` file.
+// Pomodoro returns the current Pomodoro from the `current` file. A duration
+// missing from the file is the duration from DefaultSettings, and the rounding
+// and tag separator are from settings. Use Peek to read only what is in the
+// file.
 func (c *Client) Pomodoro() (*Pomodoro, error) {
-	b, err := ioutil.ReadFile(c.CurrentFile)
+	b, err := ioutil.ReadFile(c.CurrentPath())
 	if err != nil {
 		if os.IsNotExist(err) {
-			return EmptyPomodoro(), nil
+			return c.emptyPomodoro(), nil
 		}
 		return nil, err
 	}
 
 	if len(b) == 0 {
-		return EmptyPomodoro(), nil
+		return c.emptyPomodoro(), nil
+	}
+
+	s, err := c.readSettings()
+	if err != nil {
+		return nil, err
 	}
 
 	p := NewPomodoro()
+	p.Rounding = s.Rounding
+	p.TagSeparator = s.TagSeparator
+	p.Clock = c.Clock
 	p.UnmarshalText(b)
 
 	return p, nil
 }
 
+// RemainingAt returns the remaining duration of the current Pomodoro as of t,
+// which is negative if t is after it ends, and zero if there is none.
+func (c *Client) RemainingAt(t time.Time) (time.Duration, error) {
+	p, err := c.Pomodoro()
+	if err != nil {
+		return 0, err
+	}
+
+	return p.RemainingAt(t), nil
+}
+
+// Peek returns the current Pomodoro with only what is in the `current` file.
+// Unlike Pomodoro, settings are not read, so a missing duration is zero, the
+// rounding and tag separator are empty, and tags are split by commas. It
+// returns an empty Pomodoro if the file is empty or missing, and an error if
+// the file cannot be parsed.
+func (c *Client) Peek() (*Pomodoro, error) {
+	b, err := readRaw(c.CurrentPath())
+	if err != nil {
+		return nil, err
+	}
+
+	p := c.emptyPomodoro()
+	if err := p.UnmarshalText(b); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// IsActive returns whether or not the current Pomodoro is active. It is meant
+// to be called often, so it returns without parsing if the `current` file is
+// empty or missing, and does not read settings.
+func (c *Client) IsActive() (bool, error) {
+	b, err := readRaw(c.CurrentPath())
+	if err != nil {
+		return false, err
+	}
+
+	if bytesAllWhitespace(b) {
+		return false, nil
+	}
+
+	p := NewPomodoro()
+	p.Clock = c.Clock
+	if err := p.UnmarshalText(b); err != nil {
+		return false, err
+	}
+
+	return p.IsActive(), nil
+}
+
+// RawCurrent returns the unparsed contents of the `current` file, or nil if
+// it does not exist.
+func (c *Client) RawCurrent() ([]byte, error) {
+	return readRaw(c.CurrentPath())
+}
+
+// RawHistory returns the unparsed contents of the `history` file, or nil if
+// it does not exist.
+func (c *Client) RawHistory() ([]byte, error) {
+	return readRaw(c.HistoryPath())
+}
+
+func readRaw(file string) ([]byte, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return b, nil
+}
+
 // Settings returns the settings from the `settings` file.
 func (c *Client) Settings() (*Settings, error) {
 	s, err := c.readSettings()
@@ -145,29 +578,142 @@ func (c *Client) Settings() (*Settings, error) {
 	return s, nil
 }
 
+// Sources of settings returned by ResolvedSettings.
+const (
+	// SourceFile is a value set in the `settings` file.
+	SourceFile = "file"
+
+	// SourceDefault is a value from DefaultSettings.
+	SourceDefault = "default"
+)
+
+// ResolvedSettings returns the settings like Settings, along with where each
+// value came from, keyed by setting name, such as "daily_goal". A value which
+// is unset in the `settings` file, or set to what is considered unset, such
+// as zero, comes from DefaultSettings.
+func (c *Client) ResolvedSettings() (*Settings, map[string]string, error) {
+	s, err := c.readSettings()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	set, err := s.setKeys()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sources := map[string]string{}
+	for _, key := range settingsKeys() {
+		sources[key] = SourceDefault
+		if set[key] {
+			sources[key] = SourceFile
+		}
+	}
+
+	s.SetDefaults(&DefaultSettings)
+
+	return s, sources, nil
+}
+
 // Start starts a Pomodoro by writing the current timestamp along with
 // configured defaults to the `current` file, and also records the Pomodoro in
-// the `history` file.
+// the `history` file. Any active Pomodoro is cancelled first. It returns
+// ErrInvalidDuration if the duration is still not positive after applying
+// settings, ErrDurationTooLong if it is longer than MaxDuration, or
+// ErrBelowMinDuration if it is shorter than the MinDuration setting, and leaves
+// any active Pomodoro running.
 func (c *Client) Start(p *Pomodoro) error {
-	err := c.ensureDirectory()
+	_, err := c.StartReplacing(p)
+	return err
+}
+
+// StartAt starts a Pomodoro like Start, but with the given start time instead
+// of now, such as for one which began a minute ago.
+func (c *Client) StartAt(p *Pomodoro, start time.Time) error {
+	p.StartTime = start
+	return c.Start(p)
+}
+
+// StartReplacing starts a Pomodoro like Start, and returns the active Pomodoro
+// which was cancelled to make way for it, as it was before being cancelled, or
+// nil if none was active.
+func (c *Client) StartReplacing(p *Pomodoro) (replaced *Pomodoro, err error) {
+	err = c.ensureDirectory()
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if p.Clock == nil {
+		p.Clock = c.Clock
+	}
+
+	if p.StartTime.IsZero() {
+		p.StartTime = c.now()
+	}
+
+	s, err := c.Settings()
+	if err != nil {
+		return nil, err
+	}
+
+	p.ApplySettings(s)
+
+	if err := p.Validate(s); err != nil {
+		return nil, err
 	}
 
 	current, err := c.Pomodoro()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if current.IsActive() {
+		replaced = current.Copy()
 		err = c.Cancel()
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	if p.StartTime.IsZero() {
-		p.StartTime = timeFunc()
+	if err := c.writeCurrent(p); err != nil {
+		return replaced, err
+	}
+
+	if err := c.appendHistory(p); err != nil {
+		return replaced, err
+	}
+
+	c.notify(EventStart, p)
+	return replaced, nil
+}
+
+// StartStrict starts a Pomodoro like Start, but returns ErrAlreadyActive and
+// leaves all state untouched if a Pomodoro is already active.
+func (c *Client) StartStrict(p *Pomodoro) error {
+	current, err := c.Pomodoro()
+	if err != nil {
+		return err
+	}
+
+	if current.IsActive() {
+		return ErrAlreadyActive
+	}
+
+	return c.Start(p)
+}
+
+// SetDuration changes the duration of the active Pomodoro in both the
+// `current` and `history` files, which moves when it will be done. It returns
+// ErrNotActive if no Pomodoro is active, and the errors of Pomodoro.SetDuration
+// and Validate if the duration is invalid, leaving it unchanged.
+func (c *Client) SetDuration(d time.Duration) error {
+	p, err := c.Pomodoro()
+	if err != nil {
+		return err
+	}
+
+	if !p.IsActive() {
+		return ErrNotActive
 	}
 
 	s, err := c.Settings()
@@ -175,39 +721,182 @@ func (c *Client) Start(p *Pomodoro) error {
 		return err
 	}
 
-	p.ApplySettings(s)
+	if err := p.SetDuration(d); err != nil {
+		return err
+	}
+
+	if err := p.Validate(s); err != nil {
+		return err
+	}
+
+	if err := c.ensureDirectory(); err != nil {
+		return err
+	}
 
 	if err := c.writeCurrent(p); err != nil {
 		return err
 	}
 
-	if err := c.appendHistory(p); err != nil {
+	return c.updateHistory(p)
+}
+
+// Log records a completed Pomodoro with the given start time and duration
+// directly in the `history` file, without changing the `current` file. It
+// returns ErrOverlap if it would overlap an existing Pomodoro,
+// ErrInvalidDuration if the duration is not positive, ErrDurationTooLong if it
+// is longer than MaxDuration, and ErrBelowMinDuration if it is shorter than
+// the MinDuration setting.
+func (c *Client) Log(p *Pomodoro, start time.Time, duration time.Duration) error {
+	return c.log(p, start, duration, false)
+}
+
+// ForceLog is like Log, but records the Pomodoro even if it overlaps an
+// existing one, including one with the same start time.
+func (c *Client) ForceLog(p *Pomodoro, start time.Time, duration time.Duration) error {
+	return c.log(p, start, duration, true)
+}
+
+func (c *Client) log(p *Pomodoro, start time.Time, duration time.Duration, force bool) error {
+	if duration <= 0 {
+		return ErrInvalidDuration
+	}
+
+	p.StartTime = start
+	if err := p.SetDuration(duration); err != nil {
 		return err
 	}
 
-	return nil
+	s, err := c.Settings()
+	if err != nil {
+		return err
+	}
+
+	p.ApplySettings(s)
+
+	if err := p.Validate(s); err != nil {
+		return err
+	}
+
+	history, err := c.History()
+	if err != nil {
+		return err
+	}
+
+	if !force {
+		for _, existing := range history.Pomodoros {
+			if p.Overlaps(existing) {
+				return ErrOverlap
+			}
+		}
+	}
+
+	err = c.ensureDirectory()
+	if err != nil {
+		return err
+	}
+
+	history.Pomodoros = append(history.Pomodoros, p)
+
+	return c.writeHistory(history)
 }
 
 // Finish ends the current Pomodoro by emptying the `current` file, and appending
-// the `history` with the final duration.
+// the `history` with the final duration, rounded to the nearest multiple of the
+// RoundFinishTo setting if it is set. The `history` file records durations in
+// whole minutes regardless, so the setting is mostly useful for coarser
+// rounding, such as to 5 minutes, and for the duration passed to the Notifier.
+// If the clock has gone backwards since the Pomodoro started, the duration is
+// zero and it is marked with ClockSkew.
 func (c *Client) Finish() error {
 	p, err := c.Pomodoro()
 	if err != nil {
 		return err
 	}
 
+	s, err := c.Settings()
+	if err != nil {
+		return err
+	}
+
 	err = c.Clear()
 	if err != nil {
 		return err
 	}
 
-	p.Duration = timeFunc().Sub(p.StartTime)
-	return c.updateHistory(p)
+	wasActive := p.IsActive()
+
+	elapsed := c.now().Sub(p.StartTime)
+	if elapsed < 0 && !p.IsInactive() {
+		debug("clock is %s before the start of %s", -elapsed, p)
+		elapsed = 0
+		p.ClockSkew = true
+	}
+
+	if s.RoundFinishTo > 0 {
+		elapsed = elapsed.Round(s.RoundFinishTo)
+	}
+
+	switch {
+	case p.ClockSkew:
+		p.Duration = 0
+	case !p.IsInactive():
+		p.setActualDuration(elapsed)
+	}
+
+	err = c.updateHistory(p)
+	if err != nil {
+		return err
+	}
+
+	if p.IsInactive() {
+		return nil
+	}
+
+	c.notify(EventFinish, p)
+	if wasActive {
+		c.notify(EventBreak, p)
+	}
+	return nil
+}
+
+// Refresh finishes the current Pomodoro if it is done at now, for when it was
+// never finished, such as by a daemon which polls. Unlike Finish, the time
+// after it was done is not counted, so it is recorded in the `history` file
+// with its full duration. The `current` file is emptied, and the Notifier is
+// sent EventFinish. Nothing is changed if the current Pomodoro is still active
+// at now or there is none.
+func (c *Client) Refresh(now time.Time) error {
+	p, err := c.Pomodoro()
+	if err != nil {
+		return err
+	}
+
+	if p.IsInactive() || !now.After(p.EndTime()) {
+		return nil
+	}
+
+	if err := c.Clear(); err != nil {
+		return err
+	}
+
+	if err := c.updateHistory(p); err != nil {
+		return err
+	}
+
+	c.notify(EventFinish, p)
+	return nil
 }
 
 // Cancel cancels any current Pomodoro by emptying the `current` file, and
-// removing the entry from the `history` file.
+// removing the entry from the `history` file. If the KeepCancelled setting is
+// enabled, the entry is instead marked as cancelled.
 func (c *Client) Cancel() error {
+	return c.CancelWithReason("")
+}
+
+// CancelWithReason cancels any current Pomodoro like Cancel, and records the
+// reason along with the entry when the KeepCancelled setting is enabled.
+func (c *Client) CancelWithReason(reason string) error {
 	err := c.ensureDirectory()
 	if err != nil {
 		return err
@@ -222,12 +911,35 @@ func (c *Client) Cancel() error {
 		return nil
 	}
 
+	s, err := c.Settings()
+	if err != nil {
+		return err
+	}
+
+	err = c.stash()
+	if err != nil {
+		return err
+	}
+
 	err = c.writeCurrent(EmptyPomodoro())
 	if err != nil {
 		return err
 	}
 
-	return c.deleteHistory(p)
+	if s.KeepCancelled {
+		p.setActualDuration(c.now().Sub(p.StartTime))
+		p.Cancelled = true
+		p.Reason = reason
+		err = c.updateHistory(p)
+	} else {
+		err = c.deleteHistory(p)
+	}
+	if err != nil {
+		return err
+	}
+
+	c.notify(EventCancel, p)
+	return nil
 }
 
 // Clear clears the current Pomodoro by emptying the `current` file.
@@ -237,11 +949,162 @@ func (c *Client) Clear() error {
 		return err
 	}
 
+	err = c.stash()
+	if err != nil {
+		return err
+	}
+
 	return c.writeCurrent(EmptyPomodoro())
 }
 
+// homeDir returns the user's home directory, preferring the environment over
+// user.Current, which is unavailable in some minimal environments.
+func homeDir() (string, error) {
+	key := "HOME"
+	if runtime.GOOS == "windows" {
+		key = "USERPROFILE"
+	}
+
+	if home := os.Getenv(key); home != "" {
+		return home, nil
+	}
+
+	u, err := userCurrent()
+	if err != nil {
+		return "", fmt.Errorf("unable to determine home directory: %s", err)
+	}
+
+	if u.HomeDir == "" {
+		return "", fmt.Errorf("unable to determine home directory for %s", u.Username)
+	}
+
+	return u.HomeDir, nil
+}
+
+// TrimHistory removes all but the most recent max Pomodoros from the `history`
+// file.
+func (c *Client) TrimHistory(max int) error {
+	h, err := c.History()
+	if err != nil {
+		return err
+	}
+
+	if h.Trim(max) == 0 {
+		return nil
+	}
+
+	return c.writeHistory(h)
+}
+
+// RepairHistory rewrites the `history` file in chronological order if it is
+// not already sorted, or if an incomplete last line was dropped.
+func (c *Client) RepairHistory() error {
+	h, err := c.History()
+	if err != nil {
+		return err
+	}
+
+	if h.IsSorted() && len(h.Warnings) == 0 {
+		return nil
+	}
+
+	return c.writeHistory(h)
+}
+
 func (c *Client) ensureDirectory() error {
-	return os.MkdirAll(c.Directory, 0755)
+	if c.ReadOnly {
+		return ErrReadOnly
+	}
+
+	if c.DryRun {
+		return nil
+	}
+
+	dirs := []string{
+		c.Directory,
+		filepath.Dir(c.CurrentPath()),
+		filepath.Dir(c.HistoryPath()),
+		filepath.Dir(c.SettingsPath()),
+		filepath.Dir(c.UndoPath()),
+		filepath.Dir(c.JournalPath()),
+		filepath.Dir(c.VersionPath()),
+		filepath.Dir(c.SchedulePath()),
+		filepath.Dir(c.TodayPath()),
+		filepath.Dir(c.BreaksPath()),
+	}
+
+	for _, dir := range dirs {
+		if err := os.MkdirAll(dir, c.dirPerm()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) dirPerm() os.FileMode {
+	if c.DirPerm == 0 {
+		return DirPerm
+	}
+	return c.DirPerm
+}
+
+func (c *Client) emptyPomodoro() *Pomodoro {
+	p := EmptyPomodoro()
+	p.Clock = c.Clock
+	return p
+}
+
+func (c *Client) now() time.Time {
+	return clockNow(c.Clock)
+}
+
+func (c *Client) filePerm() os.FileMode {
+	if c.FilePerm == 0 {
+		return FilePerm
+	}
+	return c.FilePerm
+}
+
+func (c *Client) writeFile(file string, b []byte) error {
+	if c.ReadOnly {
+		return ErrReadOnly
+	}
+
+	if c.DryRun {
+		c.DryRunWrites = append(c.DryRunWrites, Write{File: file, Data: b})
+		return nil
+	}
+
+	if file == c.HistoryPath() {
+		defer c.invalidateToday()
+	}
+
+	return ioutil.WriteFile(file, b, c.filePerm())
+}
+
+func (c *Client) appendFile(file string, b []byte) error {
+	if c.ReadOnly {
+		return ErrReadOnly
+	}
+
+	if c.DryRun {
+		c.DryRunWrites = append(c.DryRunWrites, Write{File: file, Data: b, Append: true})
+		return nil
+	}
+
+	if file == c.HistoryPath() {
+		defer c.invalidateToday()
+	}
+
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_APPEND|os.O_CREATE, c.filePerm())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(b)
+	return err
 }
 
 func (c *Client) writeCurrent(p *Pomodoro) error {
@@ -256,34 +1119,77 @@ func (c *Client) writeCurrent(p *Pomodoro) error {
 		}
 	}
 
-	return ioutil.WriteFile(c.CurrentFile, b, FilePerm)
+	return c.writeFile(c.CurrentPath(), b)
 }
 
 func (c *Client) appendHistory(p *Pomodoro) error {
-	if p.IsInactive() {
+	if p.IsInactive() || c.DisableHistory {
 		return nil
 	}
 
 	b, err := p.MarshalText()
+	if err != nil {
+		return err
+	}
+
+	complete, err := endsWithNewline(c.HistoryPath())
+	if err != nil {
+		return err
+	}
+
+	// Appending after an incomplete line would join the two, so rewrite the
+	// whole file instead, which also drops the line if it cannot be read. A
+	// newest-first file has to be rewritten to add the Pomodoro at the top.
+	if !complete || c.HistoryOrder == Descending {
+		history, err := c.History()
+		if err != nil {
+			return err
+		}
+
+		history.Pomodoros = append(history.Pomodoros, p)
+		return c.writeHistory(history)
+	}
 
 	b = bytes.Replace(b, charNewline, charSpace, -1)
+	b = append(b, charNewline...)
+
+	return c.appendFile(c.HistoryPath(), b)
+}
 
-	f, err := os.OpenFile(c.HistoryFile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, FilePerm)
+// endsWithNewline returns whether or not the file is empty, missing, or ends
+// with a newline.
+func endsWithNewline(file string) (bool, error) {
+	f, err := os.Open(file)
 	if err != nil {
-		return err
+		if os.IsNotExist(err) {
+			return true, nil
+		}
+		return false, err
 	}
 	defer f.Close()
 
-	_, err = f.Write(b)
+	info, err := f.Stat()
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	_, err = f.Write(charNewline)
-	return err
+	if info.Size() == 0 {
+		return true, nil
+	}
+
+	last := make([]byte, 1)
+	if _, err := f.ReadAt(last, info.Size()-1); err != nil {
+		return false, err
+	}
+
+	return last[0] == charNewline[0], nil
 }
 
 func (c *Client) updateHistory(p *Pomodoro) error {
+	if c.DisableHistory {
+		return nil
+	}
+
 	history, err := c.History()
 	if err != nil {
 		return err
@@ -295,6 +1201,10 @@ func (c *Client) updateHistory(p *Pomodoro) error {
 }
 
 func (c *Client) deleteHistory(p *Pomodoro) error {
+	if c.DisableHistory {
+		return nil
+	}
+
 	history, err := c.History()
 	if err != nil {
 		return err
@@ -306,18 +1216,84 @@ func (c *Client) deleteHistory(p *Pomodoro) error {
 }
 
 func (c *Client) writeHistory(h *History) error {
+	if c.ReadOnly {
+		return ErrReadOnly
+	}
+
 	sort.Sort(h)
 
-	b, err := h.MarshalText()
+	if c.HistoryOrder == Descending {
+		h = &History{Pomodoros: append([]*Pomodoro{}, h.Pomodoros...)}
+		h.reverse()
+	}
+
+	if c.DryRun {
+		b, err := h.MarshalText()
+		if err != nil {
+			return err
+		}
+
+		return c.writeFile(c.HistoryPath(), b)
+	}
+
+	defer c.invalidateToday()
+
+	return c.replaceFile(c.HistoryPath(), h.WriteText)
+}
+
+// replaceFile writes a file by streaming into a temporary file in the same
+// directory and renaming it over the file, so that an error partway through
+// leaves the old file intact.
+func (c *Client) replaceFile(file string, write func(io.Writer) error) error {
+	f, err := ioutil.TempFile(filepath.Dir(file), "."+filepath.Base(file)+"-*")
 	if err != nil {
 		return err
 	}
 
-	return ioutil.WriteFile(c.HistoryFile, b, FilePerm)
+	tmp := f.Name()
+	fail := func(err error) error {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := f.Chmod(c.filePerm()); err != nil && runtime.GOOS != "windows" {
+		return fail(err)
+	}
+
+	w := bufio.NewWriter(f)
+	if err := write(w); err != nil {
+		return fail(err)
+	}
+
+	if err := w.Flush(); err != nil {
+		return fail(err)
+	}
+
+	if err := f.Sync(); err != nil {
+		return fail(err)
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := renameFile(tmp, file); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	return nil
 }
 
 func (c *Client) readSettings() (*Settings, error) {
-	b, err := ioutil.ReadFile(c.SettingsFile)
+	file := c.SettingsPath()
+	b, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		file = c.SettingsPath() + ".yaml"
+		b, err = ioutil.ReadFile(file)
+	}
 	if err != nil {
 		if !os.IsNotExist(err) {
 			return nil, err
@@ -325,7 +1301,13 @@ func (c *Client) readSettings() (*Settings, error) {
 	}
 
 	s := &Settings{}
-	err = s.UnmarshalText(b)
+	if ext := filepath.Ext(file); ext == ".yaml" || ext == ".yml" {
+		err = yaml.Unmarshal(b, s)
+	} else if bytes.HasPrefix(bytes.TrimSpace(b), []byte("{")) {
+		err = json.Unmarshal(b, s)
+	} else {
+		err = s.UnmarshalText(b)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -1,9 +1,15 @@
 package openpomodoro
 
 import (
+	"errors"
+	"io"
 	"io/ioutil"
 	"log"
+	"os"
+	"os/user"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -101,9 +107,7 @@ func Test_Client(t *testing.T) {
 	}
 
 	for _, tc := range cases {
-		timeFunc = time.Now
-
-		c, err := NewClient(fixture(tc.Fixture))
+		c, err := NewClient(fixture(tc.Fixture), WithClock(NewFakeClock(time.Now())))
 		require.Nil(t, err)
 
 		for _, s := range tc.Steps {
@@ -180,10 +184,45 @@ func assertMinutesRemaining(m int) Step {
 
 func timeTravel(d time.Duration) Step {
 	return func(t *testing.T, c *Client, n string) {
-		current := timeFunc()
-		new := current.Add(d)
-		timeFunc = func() time.Time { return new }
+		c.Clock.(*FakeClock).Add(d)
+	}
+}
+
+func Test_homeDir_fromEnv(t *testing.T) {
+	key := "HOME"
+	if runtime.GOOS == "windows" {
+		key = "USERPROFILE"
 	}
+	original := os.Getenv(key)
+	defer os.Setenv(key, original)
+	defer func() { userCurrent = user.Current }()
+
+	userCurrent = func() (*user.User, error) {
+		return nil, errors.New("user: Current not implemented")
+	}
+
+	os.Setenv(key, "/home/pomodoro")
+
+	home, err := homeDir()
+	require.Nil(t, err)
+	assert.Equal(t, "/home/pomodoro", home)
+
+	os.Setenv(key, "")
+
+	_, err = homeDir()
+	assert.NotNil(t, err)
+}
+
+func Test_Paths(t *testing.T) {
+	c, err := NewClient("/tmp/pomodoro")
+	require.Nil(t, err)
+
+	assert.Equal(t, "/tmp/pomodoro/current", c.CurrentPath())
+	assert.Equal(t, "/tmp/pomodoro/history", c.HistoryPath())
+	assert.Equal(t, "/tmp/pomodoro/settings", c.SettingsPath())
+
+	c.HistoryFile = "/tmp/elsewhere/history"
+	assert.Equal(t, "/tmp/elsewhere/history", c.HistoryPath())
 }
 
 func Test_Pomodoro_simple(t *testing.T) {
@@ -204,6 +243,22 @@ func Test_Pomodoro_simple(t *testing.T) {
 	assert.Equal(t, expected, actual)
 }
 
+func Test_Pomodoro_rounding(t *testing.T) {
+	c, err := NewClient(fixture("simple"))
+	require.Nil(t, err)
+
+	require.Nil(t, ioutil.WriteFile(c.SettingsPath(), []byte("rounding=floor"), FilePerm))
+	require.Nil(t, c.Log(&Pomodoro{}, fakeTime(), 25*time.Minute))
+
+	p, err := c.Pomodoro()
+	require.Nil(t, err)
+	assert.Equal(t, RoundFloor, p.Rounding)
+
+	h, err := c.History()
+	require.Nil(t, err)
+	assert.Equal(t, RoundFloor, h.Pomodoros[0].Rounding)
+}
+
 func Test_Pomodoro_emptyFiles(t *testing.T) {
 	c, err := NewClient(fixture("empty"))
 	require.Nil(t, err)
@@ -232,6 +287,19 @@ func Test_Pomodoro_fileInsteadOfDir(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func Test_Raw(t *testing.T) {
+	c, err := NewClient(fixture("simple"))
+	require.Nil(t, err)
+
+	b, err := c.RawCurrent()
+	require.Nil(t, err)
+	assert.Equal(t, "2026-06-14T12:34:56-04:00\n", string(b))
+
+	b, err = c.RawHistory()
+	require.Nil(t, err)
+	assert.Nil(t, b)
+}
+
 func Test_Settings_defaults(t *testing.T) {
 	c, err := NewClient(fixture(""))
 	require.Nil(t, err)
@@ -255,12 +323,56 @@ func Test_Settings_exists(t *testing.T) {
 	assert.Equal(t, []string{"billable", "work"}, s.DefaultTags)
 }
 
-func Test_Start(t *testing.T) {
-	timeFunc = fakeTime
-
+func TestClient_ResolvedSettings(t *testing.T) {
 	c, err := NewClient(fixture(""))
 	require.Nil(t, err)
 
+	require.Nil(t, c.ensureDirectory())
+	require.Nil(t, ioutil.WriteFile(c.SettingsPath(), []byte("daily_goal=8 default_pomodoro_duration=20 tag_rules=\"09:00-17:00=work\""), FilePerm))
+
+	s, sources, err := c.ResolvedSettings()
+	require.Nil(t, err)
+
+	expected, err := c.Settings()
+	require.Nil(t, err)
+	assert.Equal(t, expected, s)
+
+	assert.Equal(t, SourceFile, sources["daily_goal"])
+	assert.Equal(t, SourceFile, sources["default_pomodoro_duration"])
+	assert.Equal(t, SourceFile, sources["tag_rules"])
+	assert.Equal(t, SourceDefault, sources["default_break_duration"])
+	assert.Equal(t, SourceDefault, sources["keep_cancelled"])
+	assert.Len(t, sources, len(settingsKeys()))
+}
+
+func Test_Snapshot(t *testing.T) {
+	c, err := NewClient(fixture("settings"))
+	require.Nil(t, err)
+
+	require.Nil(t, c.Start(&Pomodoro{}))
+
+	snapshot, err := c.Snapshot()
+	require.Nil(t, err)
+
+	state, err := c.CurrentState()
+	require.Nil(t, err)
+	assert.Equal(t, state, snapshot)
+
+	snapshot.Pomodoro.Tags[0] = "changed"
+	snapshot.History.Pomodoros[0].Tags[0] = "changed"
+	snapshot.Settings.DefaultTags[0] = "changed"
+
+	state, err = c.CurrentState()
+	require.Nil(t, err)
+	assert.Equal(t, []string{"billable", "work"}, state.Pomodoro.Tags)
+	assert.Equal(t, []string{"billable", "work"}, state.History.Pomodoros[0].Tags)
+	assert.Equal(t, []string{"billable", "work"}, state.Settings.DefaultTags)
+}
+
+func Test_Start(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
 	p := &Pomodoro{}
 	err = c.Start(p)
 	require.Nil(t, err)
@@ -275,10 +387,9 @@ func Test_Start(t *testing.T) {
 }
 
 func Test_Start_withOptions(t *testing.T) {
-	timeFunc = fakeTime
-	startTime := timeFunc().Add(-10 * time.Minute)
+	startTime := fakeTime().Add(-10 * time.Minute)
 
-	c, err := NewClient(fixture(""))
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
 	require.Nil(t, err)
 
 	p := &Pomodoro{
@@ -300,6 +411,363 @@ func Test_Start_withOptions(t *testing.T) {
 	assert.Equal(t, current.Tags, []string{"tag1", "tag2"})
 }
 
+func Test_StartStrict(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	require.Nil(t, c.StartStrict(&Pomodoro{Description: "first"}))
+
+	timeTravel(10*time.Minute)(t, c, "")
+
+	err = c.StartStrict(&Pomodoro{Description: "second"})
+	assert.Equal(t, ErrAlreadyActive, err)
+
+	current, err := c.Pomodoro()
+	require.Nil(t, err)
+	assert.Equal(t, "first", current.Description)
+
+	timeTravel(20*time.Minute)(t, c, "")
+
+	require.Nil(t, c.StartStrict(&Pomodoro{Description: "second"}))
+	assertHistoryLength(2)(t, c, "")
+}
+
+func Test_tagSeparator(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	require.Nil(t, c.ensureDirectory())
+	require.Nil(t, ioutil.WriteFile(c.SettingsPath(), []byte("tag_separator=/"), FilePerm))
+
+	tags := []string{"client, inc", "billable"}
+	require.Nil(t, c.Log(&Pomodoro{Tags: tags}, fakeTime().Add(-time.Hour), 25*time.Minute))
+	require.Nil(t, c.Start(&Pomodoro{Tags: tags}))
+
+	current, err := ioutil.ReadFile(c.CurrentPath())
+	require.Nil(t, err)
+	assert.Equal(t, `2016-06-14T12:34:56-04:00 duration=25 tags="client, inc/billable"`, string(current))
+
+	p, err := c.Pomodoro()
+	require.Nil(t, err)
+	assert.Equal(t, tags, p.Tags)
+
+	require.Nil(t, c.Finish())
+
+	h, err := c.History()
+	require.Nil(t, err)
+	require.Equal(t, 2, h.Count())
+	for _, p := range h.Pomodoros {
+		assert.Equal(t, tags, p.Tags)
+	}
+}
+
+func Test_multilineDescription(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	description := "first line\nsecond line"
+	require.Nil(t, c.Start(&Pomodoro{Description: description}))
+
+	current, err := c.RawCurrent()
+	require.Nil(t, err)
+	assert.NotContains(t, string(current), "\n")
+
+	p, err := c.Pomodoro()
+	require.Nil(t, err)
+	assert.Equal(t, description, p.Description)
+
+	timeTravel(25*time.Minute)(t, c, "")
+	require.Nil(t, c.Finish())
+
+	h, err := c.History()
+	require.Nil(t, err)
+	require.Equal(t, 1, h.Count())
+	assert.Equal(t, description, h.Latest().Description)
+	assert.Empty(t, h.Warnings)
+}
+
+func Test_quotedTags(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	tags := []string{"deep work", "coding"}
+	require.Nil(t, c.Start(&Pomodoro{Tags: tags}))
+
+	p, err := c.Pomodoro()
+	require.Nil(t, err)
+	assert.Equal(t, tags, p.Tags)
+
+	require.Nil(t, c.Finish())
+
+	h, err := c.History()
+	require.Nil(t, err)
+	require.Equal(t, 1, h.Count())
+	assert.Equal(t, tags, h.Latest().Tags)
+
+	require.Nil(t, ioutil.WriteFile(c.CurrentPath(),
+		[]byte(`2016-06-14T12:34:56-04:00 tags="deep work",coding duration=25`), FilePerm))
+
+	p, err = c.Pomodoro()
+	require.Nil(t, err)
+	assert.Equal(t, tags, p.Tags)
+}
+
+func Test_StartReplacing(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	replaced, err := c.StartReplacing(&Pomodoro{Description: "first", StartTime: fakeTime().Add(-12 * time.Minute)})
+	require.Nil(t, err)
+	assert.Nil(t, replaced)
+
+	replaced, err = c.StartReplacing(&Pomodoro{Description: "second"})
+	require.Nil(t, err)
+	require.NotNil(t, replaced)
+	assert.Equal(t, "first", replaced.Description)
+	assert.Equal(t, 12*time.Minute, c.Clock.Now().Sub(replaced.StartTime))
+
+	timeTravel(time.Hour)(t, c, "")
+
+	replaced, err = c.StartReplacing(&Pomodoro{Description: "third"})
+	require.Nil(t, err)
+	assert.Nil(t, replaced)
+}
+
+func TestClient_RemainingAt(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	remaining, err := c.RemainingAt(fakeTime())
+	require.Nil(t, err)
+	assert.Equal(t, time.Duration(0), remaining)
+
+	require.Nil(t, c.Start(&Pomodoro{}))
+
+	remaining, err = c.RemainingAt(fakeTime().Add(10 * time.Minute))
+	require.Nil(t, err)
+	assert.Equal(t, 15*time.Minute, remaining)
+}
+
+func Test_Peek(t *testing.T) {
+	c, err := NewClient(fixture(""))
+	require.Nil(t, err)
+
+	p, err := c.Peek()
+	require.Nil(t, err)
+	assert.Equal(t, EmptyPomodoro(), p)
+
+	require.Nil(t, c.ensureDirectory())
+	require.Nil(t, ioutil.WriteFile(c.SettingsPath(), []byte("default_pomodoro_duration=30 rounding=ceil"), FilePerm))
+	require.Nil(t, ioutil.WriteFile(c.CurrentPath(), []byte("2016-06-14T12:34:56-04:00 description=peek tags=a,b"), FilePerm))
+
+	p, err = c.Peek()
+	require.Nil(t, err)
+	assert.Equal(t, &Pomodoro{
+		StartTime:   fakeTime(),
+		Description: "peek",
+		Tags:        []string{"a", "b"},
+	}, p)
+
+	current, err := c.Pomodoro()
+	require.Nil(t, err)
+	assert.Equal(t, 25*time.Minute, current.Duration)
+	assert.Equal(t, RoundCeil, current.Rounding)
+
+	require.Nil(t, ioutil.WriteFile(c.CurrentPath(), []byte("yesterday description=peek"), FilePerm))
+	_, err = c.Peek()
+	assert.NotNil(t, err)
+}
+
+func TestClient_IsActive(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	active, err := c.IsActive()
+	require.Nil(t, err)
+	assert.False(t, active)
+
+	require.Nil(t, c.Start(&Pomodoro{}))
+
+	active, err = c.IsActive()
+	require.Nil(t, err)
+	assert.True(t, active)
+
+	timeTravel(time.Hour)(t, c, "")
+
+	active, err = c.IsActive()
+	require.Nil(t, err)
+	assert.False(t, active)
+
+	require.Nil(t, c.Clear())
+
+	active, err = c.IsActive()
+	require.Nil(t, err)
+	assert.False(t, active)
+}
+
+func Test_Log(t *testing.T) {
+	c, err := NewClient(fixture("settings"), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	start := fakeTime().Add(-2 * time.Hour)
+	require.Nil(t, c.Log(&Pomodoro{Description: "forgot"}, start, 30*time.Minute))
+
+	assertInactive(true)(t, c, "")
+
+	h, err := c.History()
+	require.Nil(t, err)
+	require.Equal(t, 1, h.Count())
+	assert.Equal(t, "forgot", h.Pomodoros[0].Description)
+	assert.Equal(t, 30*time.Minute, h.Pomodoros[0].Duration)
+	assert.Equal(t, []string{"billable", "work"}, h.Pomodoros[0].Tags)
+
+	err = c.Log(&Pomodoro{}, start.Add(20*time.Minute), 25*time.Minute)
+	assert.Equal(t, ErrOverlap, err)
+
+	require.Nil(t, c.Log(&Pomodoro{}, start.Add(30*time.Minute), 25*time.Minute))
+	require.Nil(t, c.ForceLog(&Pomodoro{}, start.Add(40*time.Minute), 25*time.Minute))
+	require.Nil(t, c.ForceLog(&Pomodoro{}, start.Add(40*time.Minute), 25*time.Minute))
+
+	assertHistoryLength(4)(t, c, "")
+}
+
+func Test_MinDuration(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	require.Nil(t, c.ensureDirectory())
+	require.Nil(t, ioutil.WriteFile(c.SettingsPath(), []byte("min_duration=5"), FilePerm))
+
+	require.Nil(t, c.Start(&Pomodoro{Description: "running"}))
+
+	assert.Equal(t, ErrBelowMinDuration, c.Start(&Pomodoro{Duration: time.Second}))
+	assert.Equal(t, ErrBelowMinDuration, c.Log(&Pomodoro{}, fakeTime().Add(-time.Hour), 4*time.Minute))
+
+	p, err := c.Pomodoro()
+	require.Nil(t, err)
+	assert.Equal(t, "running", p.Description)
+	assertHistoryLength(1)(t, c, "")
+
+	require.Nil(t, c.Log(&Pomodoro{}, fakeTime().Add(-time.Hour), 5*time.Minute))
+	assertHistoryLength(2)(t, c, "")
+}
+
+func Test_Start_invalidDuration(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	require.Nil(t, c.ensureDirectory())
+	require.Nil(t, ioutil.WriteFile(c.SettingsPath(), []byte("default_pomodoro_duration=-5"), FilePerm))
+
+	assert.Equal(t, ErrInvalidDuration, c.Start(&Pomodoro{}))
+	assertInactive(true)(t, c, "")
+	assertHistoryLength(0)(t, c, "")
+
+	defaults := DefaultSettings
+	defer func() { DefaultSettings = defaults }()
+	DefaultSettings.DefaultPomodoroDuration = 0
+	require.Nil(t, ioutil.WriteFile(c.SettingsPath(), []byte(""), FilePerm))
+
+	assert.Equal(t, ErrInvalidDuration, c.Start(&Pomodoro{}))
+	assertInactive(true)(t, c, "")
+
+	require.Nil(t, c.Start(&Pomodoro{Duration: 25 * time.Minute}))
+	assertInactive(false)(t, c, "")
+}
+
+func Test_Log_invalidDuration(t *testing.T) {
+	c, err := NewClient(fixture(""))
+	require.Nil(t, err)
+
+	assert.Equal(t, ErrInvalidDuration, c.Log(&Pomodoro{}, fakeTime(), 0))
+	assert.Equal(t, ErrInvalidDuration, c.ForceLog(&Pomodoro{}, fakeTime(), -time.Minute))
+	assert.Equal(t, ErrDurationTooLong, c.Log(&Pomodoro{}, fakeTime(), MaxDuration+time.Minute))
+
+	assertHistoryLength(0)(t, c, "")
+}
+
+func TestClient_SetDuration(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	assert.Equal(t, ErrNotActive, c.SetDuration(50*time.Minute))
+
+	require.Nil(t, c.Start(&Pomodoro{}))
+	require.Nil(t, c.SetDuration(50*time.Minute))
+
+	current, err := c.Pomodoro()
+	require.Nil(t, err)
+	assert.Equal(t, 50*time.Minute, current.Duration)
+	assert.Equal(t, fakeTime().Add(50*time.Minute), current.EndTime())
+
+	h, err := c.History()
+	require.Nil(t, err)
+	require.Equal(t, 1, h.Count())
+	assert.Equal(t, 50*time.Minute, h.Latest().Duration)
+
+	assert.Equal(t, ErrInvalidDuration, c.SetDuration(0))
+	assert.Equal(t, ErrDurationTooLong, c.SetDuration(MaxDuration+time.Minute))
+
+	current, err = c.Pomodoro()
+	require.Nil(t, err)
+	assert.Equal(t, 50*time.Minute, current.Duration)
+}
+
+func Test_StartAt(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	start := fakeTime().Add(-time.Minute)
+	require.Nil(t, c.StartAt(&Pomodoro{}, start))
+
+	current, err := c.Pomodoro()
+	require.Nil(t, err)
+	assert.True(t, current.StartTime.Equal(start))
+	assert.Equal(t, 24*time.Minute, current.Remaining())
+
+	h, err := c.History()
+	require.Nil(t, err)
+	require.Equal(t, 1, h.Count())
+	assert.True(t, h.Latest().StartTime.Equal(start))
+}
+
+func Test_StartAt_future(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	require.Nil(t, c.StartAt(&Pomodoro{}, fakeTime().Add(10*time.Minute)))
+
+	assertActive(false)(t, c, "")
+	assertDone(false)(t, c, "")
+
+	state, err := c.CurrentState()
+	require.Nil(t, err)
+	assert.Equal(t, PhaseInactive, state.Phase())
+
+	timeTravel(15*time.Minute)(t, c, "")
+	assertActive(true)(t, c, "")
+}
+
+func Test_Finish_clockSkew(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	require.Nil(t, c.Start(&Pomodoro{}))
+
+	timeTravel(-time.Hour)(t, c, "")
+	require.Nil(t, c.Finish())
+
+	b, err := ioutil.ReadFile(c.HistoryPath())
+	require.Nil(t, err)
+	assert.Equal(t, "2016-06-14T12:34:56-04:00 clock_skew=true\n", string(b))
+
+	h, err := c.History()
+	require.Nil(t, err)
+	require.Equal(t, 1, h.Count())
+	assert.True(t, h.Pomodoros[0].ClockSkew)
+	assert.Equal(t, time.Duration(0), h.Pomodoros[0].Duration)
+}
+
 func Test_Finish_active(t *testing.T) {
 	c, err := NewClient(fixture(""))
 	require.Nil(t, err)
@@ -317,6 +785,116 @@ func Test_Finish_active(t *testing.T) {
 	assert.True(t, current.IsInactive())
 }
 
+func Test_Finish_roundFinishTo(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	require.Nil(t, c.ensureDirectory())
+	require.Nil(t, ioutil.WriteFile(c.SettingsPath(), []byte("round_finish_to=1m"), FilePerm))
+
+	require.Nil(t, c.Start(&Pomodoro{}))
+	timeTravel(24*time.Minute+51*time.Second)(t, c, "")
+	require.Nil(t, c.Finish())
+
+	h, err := c.History()
+	require.Nil(t, err)
+	assert.Equal(t, 25*time.Minute, h.Latest().Duration)
+
+	require.Nil(t, ioutil.WriteFile(c.SettingsPath(), []byte("round_finish_to=5m"), FilePerm))
+
+	require.Nil(t, c.Start(&Pomodoro{}))
+	timeTravel(22*time.Minute+31*time.Second)(t, c, "")
+	require.Nil(t, c.Finish())
+
+	h, err = c.History()
+	require.Nil(t, err)
+	assert.Equal(t, 25*time.Minute, h.Latest().Duration)
+}
+
+func Test_Finish_planned(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	require.Nil(t, c.Start(&Pomodoro{}))
+	timeTravel(20*time.Minute)(t, c, "")
+	require.Nil(t, c.Finish())
+
+	h, err := c.History()
+	require.Nil(t, err)
+	assert.Equal(t, 20*time.Minute, h.Latest().Duration)
+	assert.Equal(t, 25*time.Minute, h.Latest().Planned())
+
+	require.Nil(t, c.Start(&Pomodoro{}))
+	timeTravel(25*time.Minute)(t, c, "")
+	require.Nil(t, c.Finish())
+
+	h, err = c.History()
+	require.Nil(t, err)
+	assert.Equal(t, time.Duration(0), h.Latest().PlannedDuration)
+	assert.Equal(t, 25*time.Minute, h.Latest().Planned())
+
+	require.Nil(t, c.Start(&Pomodoro{}))
+	timeTravel(20*time.Second)(t, c, "")
+	require.Nil(t, c.Finish())
+
+	h, err = c.History()
+	require.Nil(t, err)
+	assert.Equal(t, time.Duration(0), h.Latest().Duration)
+	assert.Equal(t, 25*time.Minute, h.Latest().Planned())
+	assert.Equal(t, 45*time.Minute, h.Duration())
+}
+
+func TestClient_Refresh(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	n := &recordingNotifier{}
+	c.Notifier = n
+
+	require.Nil(t, c.Refresh(fakeTime()))
+
+	require.Nil(t, c.Start(&Pomodoro{Description: "walked away"}))
+
+	require.Nil(t, c.Refresh(fakeTime().Add(25*time.Minute)))
+	assertInactive(false)(t, c, "")
+
+	require.Nil(t, c.Refresh(fakeTime().Add(2*time.Hour)))
+	assertInactive(true)(t, c, "")
+
+	h, err := c.History()
+	require.Nil(t, err)
+	require.Equal(t, 1, h.Count())
+	assert.Equal(t, "walked away", h.Latest().Description)
+	assert.Equal(t, 25*time.Minute, h.Latest().Duration)
+	assert.Equal(t, time.Duration(0), h.Latest().PlannedDuration)
+
+	assert.Equal(t, []string{EventStart, EventFinish}, n.events)
+}
+
+func TestClient_DisableHistory(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())), WithoutHistory())
+	require.Nil(t, err)
+
+	require.Nil(t, ioutil.WriteFile(c.SettingsPath(), []byte("keep_cancelled=true"), FilePerm))
+
+	require.Nil(t, c.Start(&Pomodoro{Description: "timer only"}))
+	assertInactive(false)(t, c, "")
+
+	require.Nil(t, c.SetDuration(30*time.Minute))
+	require.Nil(t, c.Defer(time.Minute))
+	require.Nil(t, c.Start(&Pomodoro{}))
+	require.Nil(t, c.Cancel())
+	require.Nil(t, c.Undo())
+	assertInactive(false)(t, c, "")
+
+	timeTravel(25*time.Minute)(t, c, "")
+	require.Nil(t, c.Finish())
+	assertInactive(true)(t, c, "")
+
+	_, err = os.Stat(c.HistoryPath())
+	assert.True(t, os.IsNotExist(err))
+}
+
 func Test_Finish_inactive(t *testing.T) {
 	c, err := NewClient(fixture(""))
 	require.Nil(t, err)
@@ -350,6 +928,65 @@ func Test_Cancel_active(t *testing.T) {
 	assert.Empty(t, history.Pomodoros)
 }
 
+func Test_CancelWithReason_keepCancelled(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	require.Nil(t, c.ensureDirectory())
+	require.Nil(t, ioutil.WriteFile(c.SettingsPath(), []byte("keep_cancelled=true"), FilePerm))
+
+	require.Nil(t, c.Start(&Pomodoro{}))
+
+	timeTravel(10*time.Minute)(t, c, "")
+
+	require.Nil(t, c.CancelWithReason("meeting"))
+
+	current, err := c.Pomodoro()
+	require.Nil(t, err)
+	assert.True(t, current.IsInactive())
+
+	history, err := c.History()
+	require.Nil(t, err)
+	require.Equal(t, 1, history.Count())
+
+	p := history.Pomodoros[0]
+	assert.True(t, p.Cancelled)
+	assert.Equal(t, "meeting", p.Reason)
+	assert.Equal(t, 10*time.Minute, p.Duration)
+	assert.Equal(t, 25*time.Minute, p.Planned())
+
+	completed, err := c.CompletedHistory()
+	require.Nil(t, err)
+	assert.Equal(t, 0, completed.Count())
+
+	abandoned, err := c.AbandonedHistory()
+	require.Nil(t, err)
+	require.Equal(t, 1, abandoned.Count())
+	assert.Equal(t, "meeting", abandoned.Latest().Reason)
+}
+
+func Test_CancelWithReason_keepCancelledImmediately(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	require.Nil(t, c.ensureDirectory())
+	require.Nil(t, ioutil.WriteFile(c.SettingsPath(), []byte("keep_cancelled=true"), FilePerm))
+
+	require.Nil(t, c.Start(&Pomodoro{}))
+	timeTravel(20*time.Second)(t, c, "")
+	require.Nil(t, c.CancelWithReason("interrupted"))
+
+	history, err := c.History()
+	require.Nil(t, err)
+	require.Equal(t, 1, history.Count())
+
+	p := history.Pomodoros[0]
+	assert.True(t, p.Cancelled)
+	assert.Equal(t, time.Duration(0), p.Duration)
+	assert.Equal(t, 25*time.Minute, p.Planned())
+	assert.Equal(t, time.Duration(0), history.Duration())
+}
+
 func Test_Cancel_finished(t *testing.T) {
 	c, err := NewClient(fixture(""))
 	require.Nil(t, err)
@@ -382,6 +1019,307 @@ func Test_Cancel_inactive(t *testing.T) {
 	require.Nil(t, err)
 }
 
+func Test_CountSince(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	require.Nil(t, c.Start(&Pomodoro{StartTime: fakeTime().Add(-2 * time.Hour)}))
+	require.Nil(t, c.Start(&Pomodoro{StartTime: fakeTime().Add(-30 * time.Minute)}))
+	require.Nil(t, c.Start(&Pomodoro{StartTime: fakeTime()}))
+
+	count, err := c.CountSince(fakeTime().Add(-time.Hour))
+	require.Nil(t, err)
+	assert.Equal(t, 2, count)
+
+	d, err := c.DurationSince(fakeTime().Add(-time.Hour))
+	require.Nil(t, err)
+	assert.Equal(t, 50*time.Minute, d)
+}
+
+func Test_RemainingToGoal(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	pomodoros, d, err := c.RemainingToGoal(fakeTime())
+	require.Nil(t, err)
+	assert.Equal(t, 0, pomodoros)
+	assert.Equal(t, time.Duration(0), d)
+
+	require.Nil(t, c.ensureDirectory())
+	require.Nil(t, ioutil.WriteFile(c.SettingsPath(), []byte("daily_goal=4 default_pomodoro_duration=20"), FilePerm))
+
+	require.Nil(t, c.Log(&Pomodoro{}, fakeTime().Add(-24*time.Hour), 20*time.Minute))
+	require.Nil(t, c.Log(&Pomodoro{}, fakeTime().Add(-2*time.Hour), 20*time.Minute))
+	require.Nil(t, c.Start(&Pomodoro{StartTime: fakeTime().Add(-10 * time.Minute)}))
+
+	pomodoros, d, err = c.RemainingToGoal(fakeTime())
+	require.Nil(t, err)
+	assert.Equal(t, 3, pomodoros)
+	assert.Equal(t, 60*time.Minute, d)
+
+	pomodoros, _, err = c.RemainingToGoal(fakeTime().Add(10 * time.Minute))
+	require.Nil(t, err)
+	assert.Equal(t, 2, pomodoros)
+
+	for i := 3; i < 5; i++ {
+		require.Nil(t, c.Log(&Pomodoro{}, fakeTime().Add(-time.Duration(i)*time.Hour), 20*time.Minute))
+	}
+
+	pomodoros, d, err = c.RemainingToGoal(fakeTime().Add(10 * time.Minute))
+	require.Nil(t, err)
+	assert.Equal(t, 0, pomodoros)
+	assert.Equal(t, time.Duration(0), d)
+}
+
+func Test_GoalPace(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	onTrack, expected, actual, err := c.GoalPace(fakeTime())
+	require.Nil(t, err)
+	assert.True(t, onTrack)
+	assert.Equal(t, 0, expected)
+	assert.Equal(t, 0, actual)
+
+	require.Nil(t, c.ensureDirectory())
+	require.Nil(t, ioutil.WriteFile(c.SettingsPath(), []byte("daily_goal=8 workday_start=8h workday_end=16h"), FilePerm))
+
+	require.Nil(t, c.Log(&Pomodoro{}, fakeTime().Add(-4*time.Hour), 25*time.Minute))
+	require.Nil(t, c.Log(&Pomodoro{}, fakeTime().Add(-2*time.Hour), 25*time.Minute))
+
+	onTrack, expected, actual, err = c.GoalPace(fakeTime())
+	require.Nil(t, err)
+	assert.False(t, onTrack)
+	assert.Equal(t, 4, expected)
+	assert.Equal(t, 2, actual)
+
+	onTrack, expected, _, err = c.GoalPace(fakeTime().Add(-3 * time.Hour))
+	require.Nil(t, err)
+	assert.True(t, onTrack)
+	assert.Equal(t, 1, expected)
+}
+
+func Test_SincePreviousBreak(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	pomodoros, d, err := c.SincePreviousBreak(fakeTime())
+	require.Nil(t, err)
+	assert.Equal(t, 0, pomodoros)
+	assert.Equal(t, time.Duration(0), d)
+
+	start := fakeTime().Add(-3 * time.Hour)
+	require.Nil(t, c.Log(&Pomodoro{}, start, 25*time.Minute))
+	require.Nil(t, c.Log(&Pomodoro{}, start.Add(time.Hour), 25*time.Minute))
+	require.Nil(t, c.Log(&Pomodoro{}, start.Add(time.Hour+27*time.Minute), 25*time.Minute))
+	require.Nil(t, c.Log(&Pomodoro{}, start.Add(time.Hour+54*time.Minute), 20*time.Minute))
+
+	pomodoros, d, err = c.SincePreviousBreak(start.Add(2*time.Hour + 16*time.Minute))
+	require.Nil(t, err)
+	assert.Equal(t, 4, pomodoros)
+	assert.Equal(t, 95*time.Minute, d)
+
+	require.Nil(t, c.BreakAt(start.Add(50*time.Minute)))
+	require.Nil(t, c.BreakAt(start.Add(2*time.Hour+20*time.Minute)))
+
+	pomodoros, d, err = c.SincePreviousBreak(start.Add(2*time.Hour + 16*time.Minute))
+	require.Nil(t, err)
+	assert.Equal(t, 3, pomodoros)
+	assert.Equal(t, 70*time.Minute, d)
+
+	pomodoros, _, err = c.SincePreviousBreak(start.Add(2*time.Hour + 20*time.Minute))
+	require.Nil(t, err)
+	assert.Equal(t, 0, pomodoros)
+
+	require.Nil(t, c.Start(&Pomodoro{StartTime: start.Add(2*time.Hour + 30*time.Minute)}))
+
+	pomodoros, _, err = c.SincePreviousBreak(start.Add(2*time.Hour + 40*time.Minute))
+	require.Nil(t, err)
+	assert.Equal(t, 0, pomodoros)
+
+	require.Nil(t, c.Break())
+
+	breaks, err := c.Breaks()
+	require.Nil(t, err)
+	require.Len(t, breaks, 3)
+	assert.True(t, breaks[2].Equal(fakeTime()))
+}
+
+func Test_HistoryOrder(t *testing.T) {
+	for order, expected := range map[HistoryOrder][]string{
+		Ascending:  {"first", "second", "third"},
+		Descending: {"third", "second", "first"},
+	} {
+		c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())), WithHistoryOrder(order))
+		require.Nil(t, err)
+
+		require.Nil(t, c.Log(&Pomodoro{Description: "second"}, fakeTime().Add(-time.Hour), 25*time.Minute))
+		require.Nil(t, c.Log(&Pomodoro{Description: "first"}, fakeTime().Add(-2*time.Hour), 25*time.Minute))
+		require.Nil(t, c.Start(&Pomodoro{Description: "third", Duration: 25 * time.Minute}))
+		timeTravel(25*time.Minute)(t, c, "")
+		require.Nil(t, c.Finish())
+
+		b, err := c.RawHistory()
+		require.Nil(t, err)
+		var descriptions []string
+		for _, line := range strings.Split(strings.TrimSpace(string(b)), "\n") {
+			p := &Pomodoro{}
+			require.Nil(t, p.UnmarshalText([]byte(line)))
+			descriptions = append(descriptions, p.Description)
+		}
+		assert.Equal(t, expected, descriptions, string(order))
+
+		h, err := c.History()
+		require.Nil(t, err)
+		assert.True(t, h.IsSorted(), string(order))
+		assert.Equal(t, "third", h.Latest().Description, string(order))
+
+		require.Nil(t, c.RepairHistory())
+		after, err := c.RawHistory()
+		require.Nil(t, err)
+		assert.Equal(t, string(b), string(after), string(order))
+	}
+}
+
+func Test_TrimHistory(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	for i := 3; i > 0; i-- {
+		p := &Pomodoro{StartTime: fakeTime().Add(-time.Duration(i) * time.Hour)}
+		require.Nil(t, c.Start(p))
+	}
+
+	require.Nil(t, c.TrimHistory(2))
+
+	h, err := c.History()
+	require.Nil(t, err)
+	require.Equal(t, 2, h.Count())
+	assert.Equal(t, fakeTime().Add(-2*time.Hour).Unix(), h.Pomodoros[0].StartTime.Unix())
+}
+
+func Test_DryRun(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	require.Nil(t, c.Start(&Pomodoro{StartTime: fakeTime().Add(-time.Hour)}))
+	require.Nil(t, c.Start(&Pomodoro{}))
+
+	n := &recordingNotifier{}
+	c.Notifier = n
+	c.DryRun = true
+
+	require.Nil(t, c.TrimHistory(1))
+	require.Nil(t, c.Cancel())
+
+	require.Equal(t, 4, len(c.DryRunWrites))
+	assert.Equal(t, Write{File: c.HistoryPath(), Data: []byte("2016-06-14T12:34:56-04:00 duration=25\n")}, c.DryRunWrites[0])
+	assert.Equal(t, c.UndoPath(), c.DryRunWrites[1].File)
+	assert.Equal(t, Write{File: c.CurrentPath()}, c.DryRunWrites[2])
+	assert.Equal(t, Write{File: c.HistoryPath(), Data: []byte("2016-06-14T11:34:56-04:00 duration=25\n")}, c.DryRunWrites[3])
+
+	assert.Empty(t, n.events)
+
+	assertActive(true)(t, c, "")
+	assertHistoryLength(2)(t, c, "")
+}
+
+func Test_RepairHistory(t *testing.T) {
+	c, err := NewClient(fixture(""))
+	require.Nil(t, err)
+
+	require.Nil(t, c.ensureDirectory())
+	require.Nil(t, ioutil.WriteFile(c.HistoryPath(), []byte(
+		"2016-06-14T12:00:00Z duration=25\n2016-06-13T12:00:00Z duration=25\n",
+	), FilePerm))
+
+	h, err := c.History()
+	require.Nil(t, err)
+	assert.False(t, h.IsSorted())
+
+	require.Nil(t, c.RepairHistory())
+
+	h, err = c.History()
+	require.Nil(t, err)
+	assert.True(t, h.IsSorted())
+	assert.Equal(t, 2, h.Count())
+}
+
+func Test_History_truncated(t *testing.T) {
+	c, err := NewClient(fixture("truncated"), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	h, err := c.History()
+	require.Nil(t, err)
+	assert.Equal(t, 2, h.Count())
+	assert.Equal(t, "second", h.Latest().Description)
+	assert.Equal(t, []error{ErrTruncatedHistory}, h.Warnings)
+
+	require.Nil(t, c.Start(&Pomodoro{Description: "fourth"}))
+
+	b, err := ioutil.ReadFile(c.HistoryPath())
+	require.Nil(t, err)
+	assert.Equal(t, `2016-06-14T09:00:00-04:00 description=first duration=25
+2016-06-14T10:00:00-04:00 description=second duration=25
+2016-06-14T12:34:56-04:00 description=fourth duration=25
+`, string(b))
+
+	h, err = c.History()
+	require.Nil(t, err)
+	assert.Empty(t, h.Warnings)
+}
+
+func Test_RepairHistory_truncated(t *testing.T) {
+	c, err := NewClient(fixture("truncated"))
+	require.Nil(t, err)
+
+	require.Nil(t, c.RepairHistory())
+
+	h, err := c.History()
+	require.Nil(t, err)
+	assert.Equal(t, 2, h.Count())
+	assert.Empty(t, h.Warnings)
+}
+
+func Test_appendHistory_missingNewline(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	require.Nil(t, c.ensureDirectory())
+	require.Nil(t, ioutil.WriteFile(c.HistoryPath(), []byte("2016-06-14T09:00:00-04:00 duration=25"), FilePerm))
+
+	require.Nil(t, c.Start(&Pomodoro{}))
+
+	h, err := c.History()
+	require.Nil(t, err)
+	assert.Equal(t, 2, h.Count())
+	assert.Empty(t, h.Warnings)
+}
+
+func Test_replaceFile_error(t *testing.T) {
+	c, err := NewClient(fixture(""))
+	require.Nil(t, err)
+
+	require.Nil(t, c.ensureDirectory())
+	original := []byte("2016-06-14T09:00:00-04:00 duration=25\n")
+	require.Nil(t, ioutil.WriteFile(c.HistoryPath(), original, FilePerm))
+
+	failure := errors.New("failure")
+	err = c.replaceFile(c.HistoryPath(), func(w io.Writer) error {
+		w.Write([]byte("partial"))
+		return failure
+	})
+	assert.Equal(t, failure, err)
+
+	b, err := ioutil.ReadFile(c.HistoryPath())
+	require.Nil(t, err)
+	assert.Equal(t, original, b)
+
+	files, err := ioutil.ReadDir(c.Directory)
+	require.Nil(t, err)
+	assert.Len(t, files, 1)
+}
+
 func fixture(f string) string {
 	tmpDir, err := ioutil.TempDir("", f)
 	if err != nil {
@@ -0,0 +1,43 @@
+package openpomodoro
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeClock(t *testing.T) {
+	clock := NewFakeClock(fakeTime())
+	assert.Equal(t, fakeTime(), clock.Now())
+
+	clock.Add(time.Minute)
+	assert.Equal(t, fakeTime().Add(time.Minute), clock.Now())
+
+	clock.Set(fakeTime())
+	assert.Equal(t, fakeTime(), clock.Now())
+}
+
+func TestClient_Clock(t *testing.T) {
+	clock := NewFakeClock(fakeTime())
+	c, err := NewClient(fixture(""), WithClock(clock))
+	require.Nil(t, err)
+
+	require.Nil(t, c.Start(&Pomodoro{}))
+
+	clock.Add(10 * time.Minute)
+
+	state, err := c.CurrentState()
+	require.Nil(t, err)
+	assert.Equal(t, 15*time.Minute, state.Pomodoro.Remaining())
+	assert.Equal(t, 15*time.Minute, state.History.Latest().Remaining())
+	assert.Equal(t, PhaseActive, state.Phase())
+
+	clock.Add(20 * time.Minute)
+
+	state, err = c.CurrentState()
+	require.Nil(t, err)
+	assert.True(t, state.Pomodoro.IsDone())
+	assert.Equal(t, PhaseDone, state.Phase())
+}
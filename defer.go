@@ -0,0 +1,64 @@
+package openpomodoro
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNotActive is returned when an action needs an active Pomodoro but there
+// is none.
+var ErrNotActive = errors.New("no Pomodoro is active")
+
+// ErrDeferPastEnd is returned by Defer when the Pomodoro would start after it
+// was due to end.
+var ErrDeferPastEnd = errors.New("cannot defer a Pomodoro past its end")
+
+// Defer moves the start of the active Pomodoro later by d, keeping its
+// duration, in both the `current` and `history` files. This is for when a
+// Pomodoro was started before the work actually began. It returns
+// ErrNotActive if no Pomodoro is active, ErrInvalidDuration if d is not
+// positive, and ErrDeferPastEnd if d is longer than the Pomodoro's duration.
+func (c *Client) Defer(d time.Duration) error {
+	if d <= 0 {
+		return ErrInvalidDuration
+	}
+
+	p, err := c.Pomodoro()
+	if err != nil {
+		return err
+	}
+
+	if !p.IsActive() {
+		return ErrNotActive
+	}
+
+	if d > p.Duration {
+		return ErrDeferPastEnd
+	}
+
+	var h *History
+	if !c.DisableHistory {
+		h, err = c.History()
+		if err != nil {
+			return err
+		}
+		h.Delete(p)
+	}
+
+	p.StartTime = p.StartTime.Add(d)
+
+	if err := c.ensureDirectory(); err != nil {
+		return err
+	}
+
+	if err := c.writeCurrent(p); err != nil {
+		return err
+	}
+
+	if h == nil {
+		return nil
+	}
+
+	h.Update(p)
+	return c.writeHistory(h)
+}
@@ -0,0 +1,39 @@
+package openpomodoro
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Defer(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	assert.Equal(t, ErrNotActive, c.Defer(time.Minute))
+
+	require.Nil(t, c.Start(&Pomodoro{Description: "later"}))
+
+	assert.Equal(t, ErrInvalidDuration, c.Defer(0))
+	assert.Equal(t, ErrDeferPastEnd, c.Defer(26*time.Minute))
+
+	timeTravel(5*time.Minute)(t, c, "")
+	require.Nil(t, c.Defer(5*time.Minute))
+
+	p, err := c.Pomodoro()
+	require.Nil(t, err)
+	assert.True(t, p.StartTime.Equal(fakeTime().Add(5*time.Minute)))
+	assert.Equal(t, 25*time.Minute, p.Duration)
+	assert.Equal(t, "later", p.Description)
+
+	h, err := c.History()
+	require.Nil(t, err)
+	require.Equal(t, 1, h.Count())
+	assert.True(t, h.Latest().StartTime.Equal(p.StartTime))
+	assert.Equal(t, "later", h.Latest().Description)
+
+	timeTravel(31*time.Minute)(t, c, "")
+	assert.Equal(t, ErrNotActive, c.Defer(time.Minute))
+}
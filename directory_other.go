@@ -0,0 +1,16 @@
+//go:build !windows
+// +build !windows
+
+package openpomodoro
+
+import "path"
+
+// defaultDirectory returns ~/.pomodoro.
+func defaultDirectory() (string, error) {
+	home, err := homeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return path.Join(home, ".pomodoro"), nil
+}
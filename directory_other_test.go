@@ -0,0 +1,24 @@
+//go:build !windows
+// +build !windows
+
+package openpomodoro
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewClient_defaultDirectory(t *testing.T) {
+	original := os.Getenv("HOME")
+	defer os.Setenv("HOME", original)
+
+	os.Setenv("HOME", "/home/pomodoro")
+
+	c, err := NewClient("")
+	require.Nil(t, err)
+	assert.Equal(t, "/home/pomodoro/.pomodoro", c.Directory)
+	assert.Equal(t, "/home/pomodoro/.pomodoro/current", c.CurrentPath())
+}
@@ -0,0 +1,35 @@
+//go:build windows
+// +build windows
+
+package openpomodoro
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// defaultDirectory returns %APPDATA%\pomodoro, falling back to
+// %LOCALAPPDATA%\pomodoro and then the home directory. An existing
+// %USERPROFILE%\.pomodoro directory is preferred so that data created before
+// this default is not hidden.
+func defaultDirectory() (string, error) {
+	home, homeErr := homeDir()
+	if homeErr == nil {
+		legacy := filepath.Join(home, ".pomodoro")
+		if info, err := os.Stat(legacy); err == nil && info.IsDir() {
+			return legacy, nil
+		}
+	}
+
+	for _, key := range []string{"APPDATA", "LOCALAPPDATA"} {
+		if dir := os.Getenv(key); dir != "" {
+			return filepath.Join(dir, "pomodoro"), nil
+		}
+	}
+
+	if homeErr != nil {
+		return "", homeErr
+	}
+
+	return filepath.Join(home, ".pomodoro"), nil
+}
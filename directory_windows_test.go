@@ -0,0 +1,42 @@
+//go:build windows
+// +build windows
+
+package openpomodoro
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewClient_defaultDirectory(t *testing.T) {
+	for _, key := range []string{"APPDATA", "LOCALAPPDATA", "USERPROFILE"} {
+		original := os.Getenv(key)
+		defer os.Setenv(key, original)
+	}
+
+	home := fixture("")
+	os.Setenv("USERPROFILE", home)
+	os.Setenv("APPDATA", `C:\Users\pomodoro\AppData\Roaming`)
+
+	c, err := NewClient("")
+	require.Nil(t, err)
+	assert.Equal(t, `C:\Users\pomodoro\AppData\Roaming\pomodoro`, c.Directory)
+
+	os.Setenv("APPDATA", "")
+	os.Setenv("LOCALAPPDATA", `C:\Users\pomodoro\AppData\Local`)
+
+	c, err = NewClient("")
+	require.Nil(t, err)
+	assert.Equal(t, `C:\Users\pomodoro\AppData\Local\pomodoro`, c.Directory)
+
+	legacy := filepath.Join(home, ".pomodoro")
+	require.Nil(t, os.MkdirAll(legacy, 0755))
+
+	c, err = NewClient("")
+	require.Nil(t, err)
+	assert.Equal(t, legacy, c.Directory)
+}
@@ -0,0 +1,62 @@
+package openpomodoro
+
+import (
+	"bytes"
+	"strconv"
+	"time"
+
+	"github.com/justincampbell/go-logfmt"
+)
+
+// parseMinutes parses a duration which is either a plain number of minutes, or
+// a value with an explicit unit such as 1500s or 0.5h.
+func parseMinutes(s string) (time.Duration, error) {
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(f * float64(time.Minute)), nil
+	}
+
+	return time.ParseDuration(s)
+}
+
+// normalizeMinutes rewrites the values of the given keys in a logfmt record
+// from any duration with an explicit unit into plain minutes, which is what
+// the logfmt tags expect. Records which cannot be scanned are returned as-is so
+// that logfmt.Unmarshal can report the error.
+func normalizeMinutes(b []byte, keys ...string) ([]byte, error) {
+	d := logfmt.NewDecoder(bytes.NewReader(b))
+	if !d.ScanRecord() {
+		return b, nil
+	}
+
+	var keyvals []interface{}
+	changed := false
+
+	for d.ScanKeyval() {
+		k := string(d.Key())
+		v := string(d.Value())
+
+		for _, key := range keys {
+			if k != key || v == "" {
+				continue
+			}
+			if _, err := strconv.ParseFloat(v, 64); err == nil {
+				continue
+			}
+
+			duration, err := parseMinutes(v)
+			if err != nil {
+				return nil, err
+			}
+			v = strconv.FormatFloat(duration.Minutes(), 'f', -1, 64)
+			changed = true
+		}
+
+		keyvals = append(keyvals, k, v)
+	}
+
+	if d.Err() != nil || !changed {
+		return b, nil
+	}
+
+	return logfmt.MarshalKeyvals(keyvals...)
+}
@@ -0,0 +1,66 @@
+package openpomodoro
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseMinutes(t *testing.T) {
+	cases := map[string]time.Duration{
+		"25":    25 * time.Minute,
+		"1.5":   90 * time.Second,
+		"25m":   25 * time.Minute,
+		"1500s": 25 * time.Minute,
+		"0.5h":  30 * time.Minute,
+	}
+
+	for s, expected := range cases {
+		actual, err := parseMinutes(s)
+		require.Nil(t, err, s)
+		assert.Equal(t, expected, actual, s)
+	}
+
+	_, err := parseMinutes("soon")
+	assert.NotNil(t, err)
+}
+
+func Test_UnmarshalText_durationUnits(t *testing.T) {
+	cases := map[string]string{
+		"duration=25":    "2026-06-14T12:34:56-04:00 description=\"with units\" duration=25",
+		"duration=25m":   "2026-06-14T12:34:56-04:00 description=\"with units\" duration=25",
+		"duration=1500s": "2026-06-14T12:34:56-04:00 description=\"with units\" duration=25",
+		"duration=0.5h":  "2026-06-14T12:34:56-04:00 description=\"with units\" duration=30",
+	}
+
+	for attribute, expected := range cases {
+		p := &Pomodoro{}
+		err := p.UnmarshalText([]byte(`2026-06-14T12:34:56-04:00 description="with units" ` + attribute))
+		require.Nil(t, err, attribute)
+
+		actual, err := p.MarshalText()
+		require.Nil(t, err, attribute)
+		assert.Equal(t, expected, string(actual), attribute)
+	}
+}
+
+func Test_UnmarshalText_invalidDurationUnit(t *testing.T) {
+	p := &Pomodoro{}
+	err := p.UnmarshalText([]byte(`2026-06-14T12:34:56-04:00 duration=soon`))
+	assert.NotNil(t, err)
+}
+
+func Test_Settings_UnmarshalText_durationUnits(t *testing.T) {
+	s := &Settings{}
+
+	err := s.UnmarshalText([]byte(`
+	  default_break_duration=300s
+	  default_pomodoro_duration=0.5h
+	`))
+	require.Nil(t, err)
+
+	assert.Equal(t, 5*time.Minute, s.DefaultBreakDuration)
+	assert.Equal(t, 30*time.Minute, s.DefaultPomodoroDuration)
+}
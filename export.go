@@ -0,0 +1,67 @@
+package openpomodoro
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// csvHeader is the first row written by WriteCSV.
+var csvHeader = []string{
+	"start_time", "end_time", "duration", "description", "notes", "tags",
+	"color", "cancelled", "reason",
+}
+
+// WriteCSV writes the History to w as CSV, with a header row and then one row
+// per Pomodoro. Times are in TimeFormat, the duration is in minutes, and tags
+// are separated by commas.
+func (h History) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, p := range h.Pomodoros {
+		record := []string{
+			p.StartTime.Format(TimeFormat),
+			p.EndTime().Format(TimeFormat),
+			strconv.Itoa(p.DurationMinutes()),
+			p.Description,
+			p.Notes,
+			strings.Join(p.Tags, ","),
+			p.Color,
+			strconv.FormatBool(p.Cancelled),
+			p.Reason,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// ExportJSON writes the Pomodoros from the `history` file to w as a JSON
+// object, in the same format as History.MarshalJSON.
+func (c *Client) ExportJSON(w io.Writer) error {
+	h, err := c.History()
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(w).Encode(h)
+}
+
+// ExportCSV writes the Pomodoros from the `history` file to w as CSV, in the
+// same format as History.WriteCSV.
+func (c *Client) ExportCSV(w io.Writer) error {
+	h, err := c.History()
+	if err != nil {
+		return err
+	}
+
+	return h.WriteCSV(w)
+}
@@ -0,0 +1,58 @@
+package openpomodoro
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory_WriteCSV(t *testing.T) {
+	h := &History{Pomodoros: []*Pomodoro{
+		{
+			StartTime:   time.Date(2016, 06, 14, 12, 0, 0, 0, time.UTC),
+			Duration:    25 * time.Minute,
+			Description: "write, then \"edit\"",
+			Tags:        []string{"a", "b"},
+		},
+		{
+			StartTime: time.Date(2016, 06, 14, 13, 0, 0, 0, time.UTC),
+			Duration:  10 * time.Minute,
+			Cancelled: true,
+			Reason:    "meeting",
+		},
+	}}
+
+	buf := &bytes.Buffer{}
+	require.Nil(t, h.WriteCSV(buf))
+	assert.Equal(t, `start_time,end_time,duration,description,notes,tags,color,cancelled,reason
+2016-06-14T12:00:00Z,2016-06-14T12:25:00Z,25,"write, then ""edit""",,"a,b",,false,
+2016-06-14T13:00:00Z,2016-06-14T13:10:00Z,10,,,,,true,meeting
+`, buf.String())
+}
+
+func TestClient_Export(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	require.Nil(t, c.Log(&Pomodoro{Description: "exported"}, fakeTime().Add(-time.Hour), 25*time.Minute))
+
+	h, err := c.History()
+	require.Nil(t, err)
+
+	buf := &bytes.Buffer{}
+	require.Nil(t, c.ExportJSON(buf))
+	expected, err := json.Marshal(h)
+	require.Nil(t, err)
+	assert.Equal(t, string(expected)+"\n", buf.String())
+
+	buf.Reset()
+	require.Nil(t, c.ExportCSV(buf))
+	csv := &bytes.Buffer{}
+	require.Nil(t, h.WriteCSV(csv))
+	assert.Equal(t, csv.String(), buf.String())
+	assert.Contains(t, buf.String(), "exported")
+}
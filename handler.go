@@ -0,0 +1,139 @@
+package openpomodoro
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+)
+
+// Status is the current status of the Client as served by StatusHandler.
+type Status struct {
+	Pomodoro         *Pomodoro `json:"pomodoro"`
+	Active           bool      `json:"active"`
+	Done             bool      `json:"done"`
+	RemainingSeconds int       `json:"remaining_seconds"`
+}
+
+// statusRequest is the JSON body of a POST to StatusHandler.
+type statusRequest struct {
+	Action      string   `json:"action"`
+	Description string   `json:"description"`
+	Duration    string   `json:"duration"`
+	Tags        []string `json:"tags"`
+}
+
+// StatusHandler returns an http.Handler which serves the current status as
+// JSON on GET. On POST, the body must be a JSON object whose `action` of
+// start, finish, or cancel is performed first. Starting also accepts
+// `description`, `duration`, and `tags` fields.
+//
+// POST requests must have a Content-Type of application/json, which browsers
+// will not send cross-origin without a preflight, so other sites cannot change
+// the state. Invalid requests are answered with 400. Errors performing the
+// action are answered with the status from actionStatus.
+func (c *Client) StatusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPost:
+			mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if mediaType != "application/json" {
+				http.Error(w, http.StatusText(http.StatusUnsupportedMediaType), http.StatusUnsupportedMediaType)
+				return
+			}
+
+			action, err := c.readAction(r)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			if err := action(); err != nil {
+				http.Error(w, err.Error(), actionStatus(err))
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		status, err := c.status()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(status)
+	})
+}
+
+// actionStatus returns the HTTP status for an error performing an action: 400
+// for an invalid duration, 409 for an action which does not fit the current
+// state, 403 for a ReadOnly Client, and 500 for anything else, such as failing
+// to read or write a file.
+func actionStatus(err error) int {
+	switch err {
+	case ErrBelowMinDuration, ErrDurationTooLong, ErrInvalidDuration:
+		return http.StatusBadRequest
+	case ErrNotActive, ErrAlreadyActive:
+		return http.StatusConflict
+	case ErrReadOnly:
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// readAction decodes the body of a POST to StatusHandler, and returns the
+// action to perform.
+func (c *Client) readAction(r *http.Request) (func() error, error) {
+	var req statusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	switch req.Action {
+	case "start":
+		p := &Pomodoro{Description: req.Description, Tags: req.Tags}
+
+		if req.Duration != "" {
+			duration, err := parseMinutes(req.Duration)
+			if err != nil {
+				return nil, err
+			}
+			if err := p.SetDuration(duration); err != nil {
+				return nil, err
+			}
+		}
+
+		return func() error { return c.Start(p) }, nil
+	case "finish":
+		return c.Finish, nil
+	case "cancel":
+		return c.Cancel, nil
+	default:
+		return nil, fmt.Errorf("unknown action %q", req.Action)
+	}
+}
+
+func (c *Client) status() (*Status, error) {
+	p, err := c.Pomodoro()
+	if err != nil {
+		return nil, err
+	}
+
+	status := &Status{
+		Active:           p.IsActive(),
+		Done:             p.IsDone(),
+		RemainingSeconds: round(p.Remaining().Seconds()),
+	}
+
+	if !p.IsInactive() {
+		status.Pomodoro = p
+	}
+
+	return status, nil
+}
@@ -0,0 +1,141 @@
+package openpomodoro
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_StatusHandler(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	h := c.StatusHandler()
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t,
+		`{"pomodoro":null,"active":false,"done":false,"remaining_seconds":0}`+"\n",
+		w.Body.String())
+
+	r := httptest.NewRequest("POST", "/", strings.NewReader(
+		`{"action":"start","description":"from the web","duration":"20","tags":["a","b"]}`))
+	r.Header.Set("Content-Type", "application/json")
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t,
+		`{"pomodoro":{"start_time":"2016-06-14T12:34:56-04:00","description":"from the web","duration":20,"end_time":"2016-06-14T12:54:56-04:00","tags":["a","b"]},"active":true,"done":false,"remaining_seconds":1200}`+"\n",
+		w.Body.String())
+
+	p, err := c.Pomodoro()
+	require.Nil(t, err)
+	assert.Equal(t, 20*time.Minute, p.Duration)
+
+	r = httptest.NewRequest("POST", "/", strings.NewReader(`{"action":"cancel"}`))
+	r.Header.Set("Content-Type", "application/json; charset=utf-8")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assertActive(false)(t, c, "")
+}
+
+func Test_StatusHandler_errors(t *testing.T) {
+	c, err := NewClient(fixture(""))
+	require.Nil(t, err)
+
+	h := c.StatusHandler()
+
+	post := func(contentType string, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", "/", strings.NewReader(body))
+		r.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	w := post("application/json", `{"action":"pause"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "unknown action \"pause\"\n", w.Body.String())
+
+	w = post("application/json", `{"action":"start","duration":"soon"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = post("application/json", `action=start`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = post("application/x-www-form-urlencoded", `action=start`)
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	assertActive(false)(t, c, "")
+
+	w = post("text/plain", `{"action":"start"}`)
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	assertActive(false)(t, c, "")
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("DELETE", "/", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "GET, POST", w.Header().Get("Allow"))
+}
+
+func Test_StatusHandler_actionErrors(t *testing.T) {
+	c, err := NewClient(fixture(""))
+	require.Nil(t, err)
+
+	require.Nil(t, c.ensureDirectory())
+	require.Nil(t, os.Mkdir(c.CurrentPath(), 0755))
+
+	r := httptest.NewRequest("POST", "/", strings.NewReader(`{"action":"start"}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	c.StatusHandler().ServeHTTP(w, r)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func Test_StatusHandler_clientErrors(t *testing.T) {
+	dir := fixture("")
+	c, err := NewClient(dir)
+	require.Nil(t, err)
+
+	require.Nil(t, c.ensureDirectory())
+	require.Nil(t, ioutil.WriteFile(c.SettingsPath(), []byte("min_duration=10"), FilePerm))
+
+	post := func(c *Client, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", "/", strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		c.StatusHandler().ServeHTTP(w, r)
+		return w
+	}
+
+	w := post(c, `{"action":"start","duration":"5"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, ErrBelowMinDuration.Error()+"\n", w.Body.String())
+
+	readOnly, err := NewClient(dir, WithReadOnly())
+	require.Nil(t, err)
+
+	w = post(readOnly, `{"action":"start"}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assertActive(false)(t, c, "")
+}
+
+func Test_actionStatus(t *testing.T) {
+	assert.Equal(t, http.StatusBadRequest, actionStatus(ErrBelowMinDuration))
+	assert.Equal(t, http.StatusBadRequest, actionStatus(ErrDurationTooLong))
+	assert.Equal(t, http.StatusBadRequest, actionStatus(ErrInvalidDuration))
+	assert.Equal(t, http.StatusConflict, actionStatus(ErrNotActive))
+	assert.Equal(t, http.StatusConflict, actionStatus(ErrAlreadyActive))
+	assert.Equal(t, http.StatusForbidden, actionStatus(ErrReadOnly))
+	assert.Equal(t, http.StatusInternalServerError, actionStatus(os.ErrPermission))
+}
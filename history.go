@@ -3,13 +3,25 @@ package openpomodoro
 import (
 	"bytes"
 	"encoding/json"
+	"io"
+	"math"
 	"sort"
+	"strings"
 	"time"
 )
 
 // History is a collection of Pomodoros.
 type History struct {
 	Pomodoros []*Pomodoro `json:"pomodoros"`
+
+	// DayStartOffset is how long after midnight each day begins, for methods
+	// which group Pomodoros by day. It is set from settings when read by a
+	// Client, and carried over to collections derived from this one.
+	DayStartOffset time.Duration `json:"-"`
+
+	// Warnings are problems which were recovered from while reading the
+	// collection, such as ErrTruncatedHistory.
+	Warnings []error `json:"-"`
 }
 
 // sort.Interface
@@ -25,23 +37,73 @@ func (h History) MarshalJSON() ([]byte, error) {
 	return json.Marshal((alias)(h))
 }
 
+// UnmarshalJSON implements json.Unmarshaler, reading what MarshalJSON writes.
+func (h *History) UnmarshalJSON(b []byte) error {
+	type alias History
+	return json.Unmarshal(b, (*alias)(h))
+}
+
 // MarshalText implements encoding.TextMarshaler. It returns a byte slice of
 // each Pomodoro in the History also marshaled, separated by a newline.
 func (h History) MarshalText() ([]byte, error) {
-	var bs [][]byte
+	buf := &bytes.Buffer{}
+	if err := h.WriteText(buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
 
+// WriteText writes each Pomodoro in the History to w in the same format as
+// MarshalText, one at a time, so that the whole History does not need to be
+// held in memory as text.
+func (h History) WriteText(w io.Writer) error {
 	for _, p := range h.Pomodoros {
 		b, err := p.MarshalText()
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		bs = append(bs, b)
+		if _, err := w.Write(append(b, charNewline...)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IsSorted returns whether or not the collection is in chronological order.
+func (h *History) IsSorted() bool {
+	return sort.IsSorted(h)
+}
+
+// Copy returns a deep copy of the collection and each Pomodoro in it.
+func (h *History) Copy() *History {
+	c := &History{DayStartOffset: h.DayStartOffset}
+	if h.Pomodoros != nil {
+		c.Pomodoros = make([]*Pomodoro, len(h.Pomodoros))
+	}
+	for i, pomodoro := range h.Pomodoros {
+		c.Pomodoros[i] = pomodoro.Copy()
+	}
+
+	return c
+}
+
+// Equal returns whether or not another collection has Pomodoros with the same
+// content in the same order. See Pomodoro.Equal.
+func (h *History) Equal(o *History) bool {
+	if len(h.Pomodoros) != len(o.Pomodoros) {
+		return false
 	}
 
-	bs = append(bs, nil)
+	for i, p := range h.Pomodoros {
+		if !p.Equal(o.Pomodoros[i]) {
+			return false
+		}
+	}
 
-	return bytes.Join(bs, charNewline), nil
+	return true
 }
 
 // Latest sorts the collection and then returns the latest Pomodoro.
@@ -61,19 +123,297 @@ func (h *History) Count() int {
 	return len(h.Pomodoros)
 }
 
-// Date returns a new History collection for the given date.
+// CompletionRate returns the fraction of Pomodoros in the collection which
+// were completed, from 0 to 1. It returns 0 for an empty collection. Use Range
+// or Date first to compute the rate over a period of time.
+func (h *History) CompletionRate() float64 {
+	if len(h.Pomodoros) == 0 {
+		return 0
+	}
+
+	completed := 0
+	for _, pomodoro := range h.Pomodoros {
+		if pomodoro.IsCompleted() {
+			completed++
+		}
+	}
+
+	return float64(completed) / float64(len(h.Pomodoros))
+}
+
+// Tags returns the sorted, unique tags across all Pomodoros in the collection.
+func (h *History) Tags() []string {
+	tags := []string{}
+	for tag := range h.TagCounts() {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	return tags
+}
+
+// TagCounts returns the number of Pomodoros in the collection with each tag.
+func (h *History) TagCounts() map[string]int {
+	counts := map[string]int{}
+	for _, pomodoro := range h.Pomodoros {
+		for _, tag := range pomodoro.Tags {
+			counts[tag]++
+		}
+	}
+
+	return counts
+}
+
+// CountWithTag returns the number of Pomodoros in the collection with the tag,
+// as decided by Pomodoro.TagsContain.
+func (h *History) CountWithTag(tag string) int {
+	return h.WithTag(tag).Count()
+}
+
+// WithTag returns a new History collection of the Pomodoros with the tag, as
+// decided by Pomodoro.TagsContain.
+func (h *History) WithTag(tag string) *History {
+	return h.filter(func(p *Pomodoro) bool { return p.TagsContain(tag) })
+}
+
+// TagCooccurrence returns how many Pomodoros in the collection had each pair
+// of different tags together. It is symmetric, so that the count for a and b
+// is under both [a][b] and [b][a]. Tags repeated on one Pomodoro are counted
+// once.
+func (h *History) TagCooccurrence() map[string]map[string]int {
+	counts := map[string]map[string]int{}
+
+	for _, pomodoro := range h.Pomodoros {
+		seen := map[string]bool{}
+		var tags []string
+		for _, tag := range pomodoro.Tags {
+			if !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+
+		for _, a := range tags {
+			for _, b := range tags {
+				if a == b {
+					continue
+				}
+				if counts[a] == nil {
+					counts[a] = map[string]int{}
+				}
+				counts[a][b]++
+			}
+		}
+	}
+
+	return counts
+}
+
+// DescriptionCount is how many Pomodoros had a description.
+type DescriptionCount struct {
+	Description string
+	Count       int
+}
+
+// TopDescriptions returns the n most frequent descriptions in the collection,
+// most frequent first, and then in alphabetical order. Descriptions are
+// compared ignoring case and surrounding whitespace, and are returned as they
+// were first written. Empty descriptions are skipped. All are returned if n is
+// not positive.
+func (h *History) TopDescriptions(n int) []DescriptionCount {
+	index := map[string]int{}
+	counts := []DescriptionCount{}
+
+	sorted := &History{Pomodoros: append([]*Pomodoro{}, h.Pomodoros...)}
+	sort.Sort(sorted)
+
+	for _, pomodoro := range sorted.Pomodoros {
+		description := strings.TrimSpace(pomodoro.Description)
+		if description == "" {
+			continue
+		}
+
+		key := strings.ToLower(description)
+		i, ok := index[key]
+		if !ok {
+			i = len(counts)
+			index[key] = i
+			counts = append(counts, DescriptionCount{Description: description})
+		}
+		counts[i].Count++
+	}
+
+	sort.SliceStable(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Description < counts[j].Description
+	})
+
+	if n > 0 && n < len(counts) {
+		counts = counts[:n]
+	}
+
+	return counts
+}
+
+// Date returns a new History collection for the given date, where the day
+// begins at the collection's DayStartOffset. A Pomodoro started exactly when
+// the next day begins belongs only to the next day.
 func (h *History) Date(date time.Time) *History {
+	return h.DateWithOffset(date, h.DayStartOffset)
+}
+
+// DateWithOffset returns a new History collection for the given date, where
+// the day begins at the offset after midnight instead of at midnight.
+func (h *History) DateWithOffset(date time.Time, offset time.Duration) *History {
 	y, m, d := date.Date()
 
-	today := time.Date(y, m, d, 0, 0, 0, 0, date.Location())
+	today := time.Date(y, m, d, 0, 0, 0, 0, date.Location()).Add(offset)
 	tomorrow := today.AddDate(0, 0, 1)
 
-	return h.Range(today, tomorrow)
+	return h.Range(today, tomorrow.Add(-time.Nanosecond))
+}
+
+// StartOfDay returns the time that the day containing t begins, considering
+// the DayStartOffset. With an offset of 4 hours, the day containing 1am began
+// at 4am the previous day.
+func (h *History) StartOfDay(t time.Time) time.Time {
+	y, m, d := t.Add(-h.DayStartOffset).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location()).Add(h.DayStartOffset)
+}
+
+// completedOnDay returns the number of Pomodoros completed by now on the day
+// containing now, so that one which is running is not counted.
+func (h *History) completedOnDay(now time.Time) int {
+	completed := 0
+	for _, p := range h.Date(h.StartOfDay(now)).Pomodoros {
+		if p.IsCompleted() && !p.EndTime().After(now) {
+			completed++
+		}
+	}
+
+	return completed
+}
+
+// ActiveDays returns the distinct days with at least one Pomodoro, in
+// chronological order, as midnight in the given location. Days begin at the
+// DayStartOffset.
+func (h *History) ActiveDays(loc *time.Location) []time.Time {
+	seen := map[string]bool{}
+	days := []time.Time{}
+
+	for _, p := range h.Pomodoros {
+		y, m, d := p.StartTime.In(loc).Add(-h.DayStartOffset).Date()
+		day := time.Date(y, m, d, 0, 0, 0, 0, loc)
+
+		key := day.Format(DateFormat)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		days = append(days, day)
+	}
+
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+
+	return days
+}
+
+// DateIndex is a History collection grouped by day, for looking up many days
+// without scanning the whole collection each time.
+type DateIndex struct {
+	loc    *time.Location
+	offset time.Duration
+	days   map[string]*History
+}
+
+// IndexByDate groups the collection by day in the given location, where days
+// begin at the DayStartOffset. The index is a snapshot, so it does not reflect
+// later changes to the collection.
+func (h *History) IndexByDate(loc *time.Location) *DateIndex {
+	index := &DateIndex{loc: loc, offset: h.DayStartOffset, days: map[string]*History{}}
+
+	for _, p := range h.Pomodoros {
+		key := p.StartTime.In(loc).Add(-h.DayStartOffset).Format(DateFormat)
+
+		day, ok := index.days[key]
+		if !ok {
+			day = &History{DayStartOffset: h.DayStartOffset}
+			index.days[key] = day
+		}
+		day.Pomodoros = append(day.Pomodoros, p)
+	}
+
+	return index
+}
+
+// Day returns the History collection for the day containing the given date in
+// the index's location, which is empty if there were no Pomodoros that day.
+func (i *DateIndex) Day(date time.Time) *History {
+	if day, ok := i.days[date.In(i.loc).Format(DateFormat)]; ok {
+		return day
+	}
+
+	return &History{DayStartOffset: i.offset}
+}
+
+// GoalMetDays returns how many days between the start and end times had at
+// least goal completed Pomodoros, with days in the given location beginning
+// at the DayStartOffset. It returns 0 if no goal is set.
+func (h *History) GoalMetDays(goal int, start time.Time, end time.Time, loc *time.Location) int {
+	if goal <= 0 {
+		return 0
+	}
+
+	y, m, d := start.In(loc).Add(-h.DayStartOffset).Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, loc)
+
+	met := 0
+	for ; day.Before(end); day = day.AddDate(0, 0, 1) {
+		completed := 0
+		for _, pomodoro := range h.Date(day).Pomodoros {
+			if pomodoro.IsCompleted() {
+				completed++
+			}
+		}
+
+		if completed >= goal {
+			met++
+		}
+	}
+
+	return met
+}
+
+// AllocateAcrossDays returns the total duration of Pomodoros for each day in
+// the given location, keyed by DateFormat. Pomodoros which cross midnight have
+// their duration split between the days they cover.
+func (h *History) AllocateAcrossDays(loc *time.Location) map[string]time.Duration {
+	days := map[string]time.Duration{}
+
+	for _, pomodoro := range h.Pomodoros {
+		start := pomodoro.StartTime.In(loc)
+		end := pomodoro.EndTime().In(loc)
+
+		for start.Before(end) {
+			y, m, d := start.Date()
+			next := time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+			if end.Before(next) {
+				next = end
+			}
+
+			days[start.Format(DateFormat)] += next.Sub(start)
+			start = next
+		}
+	}
+
+	return days
 }
 
 // Range returns a new History collection between the start and end times.
 func (h *History) Range(start time.Time, end time.Time) *History {
-	result := &History{}
+	result := &History{DayStartOffset: h.DayStartOffset}
 	for _, pomodoro := range h.Pomodoros {
 		if t := pomodoro.StartTime; t.Before(start) || t.After(end) {
 			continue
@@ -84,6 +424,334 @@ func (h *History) Range(start time.Time, end time.Time) *History {
 	return result
 }
 
+// Since returns a new History collection of Pomodoros started at or after the
+// given time.
+func (h *History) Since(t time.Time) *History {
+	result := &History{DayStartOffset: h.DayStartOffset}
+	for _, pomodoro := range h.Pomodoros {
+		if pomodoro.StartTime.Before(t) {
+			continue
+		}
+		result.Pomodoros = append(result.Pomodoros, pomodoro)
+	}
+
+	return result
+}
+
+// Completed returns a new History collection of the Pomodoros which were
+// completed, as decided by Pomodoro.IsCompleted.
+func (h *History) Completed() *History {
+	return h.filter(func(p *Pomodoro) bool { return p.IsCompleted() })
+}
+
+// Abandoned returns a new History collection of the Pomodoros which were not
+// completed, such as those which were cancelled.
+func (h *History) Abandoned() *History {
+	return h.filter(func(p *Pomodoro) bool { return !p.IsCompleted() })
+}
+
+func (h *History) filter(keep func(*Pomodoro) bool) *History {
+	result := &History{DayStartOffset: h.DayStartOffset}
+	for _, pomodoro := range h.Pomodoros {
+		if keep(pomodoro) {
+			result.Pomodoros = append(result.Pomodoros, pomodoro)
+		}
+	}
+
+	return result
+}
+
+// Duration returns the total duration of all Pomodoros in the collection.
+func (h *History) Duration() time.Duration {
+	var total time.Duration
+	for _, pomodoro := range h.Pomodoros {
+		total += pomodoro.Duration
+	}
+
+	return total
+}
+
+// TotalDurationRounded returns the total duration of all Pomodoros in the
+// collection rounded to the nearest multiple of to, with halfway values
+// rounded up. It returns the total unchanged if to is not positive.
+func (h *History) TotalDurationRounded(to time.Duration) time.Duration {
+	return h.Duration().Round(to)
+}
+
+// DurationPercentile returns the pth percentile, from 0 to 1, of the durations
+// of completed Pomodoros in the collection, interpolating between the two
+// nearest durations. A p outside of 0 to 1 is clamped, so that 0 is the
+// shortest and 1 the longest. It returns 0 if there are no completed
+// Pomodoros.
+func (h *History) DurationPercentile(p float64) time.Duration {
+	var durations []time.Duration
+	for _, pomodoro := range h.Pomodoros {
+		if pomodoro.IsCompleted() {
+			durations = append(durations, pomodoro.Duration)
+		}
+	}
+
+	if len(durations) == 0 {
+		return 0
+	}
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	p = math.Max(0, math.Min(1, p))
+	rank := p * float64(len(durations)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	fraction := rank - float64(lower)
+
+	return durations[lower] + time.Duration(fraction*float64(durations[upper]-durations[lower]))
+}
+
+// Compact returns a new, sorted History collection where consecutive completed
+// Pomodoros with the same description and tags, which started less than gap
+// after the previous one ended, are merged into a single Pomodoro with their
+// durations summed. The collection itself is not changed.
+func (h *History) Compact(gap time.Duration) *History {
+	sorted := h.Copy()
+	sort.Sort(sorted)
+
+	result := &History{DayStartOffset: h.DayStartOffset}
+	var last *Pomodoro
+	var lastEnd time.Time
+
+	for _, p := range sorted.Pomodoros {
+		if last != nil && p.IsCompleted() && last.IsCompleted() &&
+			p.Description == last.Description && equalStrings(p.Tags, last.Tags) &&
+			p.StartTime.Sub(lastEnd) < gap {
+			last.Duration += p.Duration
+			lastEnd = p.EndTime()
+			continue
+		}
+
+		result.Pomodoros = append(result.Pomodoros, p)
+		last = p
+		lastEnd = p.EndTime()
+	}
+
+	return result
+}
+
+// InferDurations returns a new, sorted History collection where each
+// Pomodoro's duration is the time until the next one started, up to
+// defaultDur, and the last Pomodoro's duration is defaultDur. This is useful
+// for history which only recorded start times. The collection itself is not
+// changed.
+func (h *History) InferDurations(defaultDur time.Duration) *History {
+	result := h.Copy()
+	sort.Sort(result)
+
+	for i, p := range result.Pomodoros {
+		p.Duration = defaultDur
+		if i+1 < len(result.Pomodoros) {
+			if gap := result.Pomodoros[i+1].StartTime.Sub(p.StartTime); gap < defaultDur {
+				p.Duration = gap
+			}
+		}
+	}
+
+	return result
+}
+
+// WithDefaultDurations returns a copy of the collection where each Pomodoro
+// with a zero duration, such as one which was never finished, has a duration
+// of d instead. Cancelled Pomodoros are left as they are. Unlike
+// InferDurations, the time until the next Pomodoro is not considered. The
+// collection itself is not changed, so write the copy to keep the durations.
+func (h *History) WithDefaultDurations(d time.Duration) *History {
+	result := h.Copy()
+	for _, p := range result.Pomodoros {
+		if p.Duration == 0 && !p.Cancelled {
+			p.Duration = d
+		}
+	}
+
+	return result
+}
+
+// Trim sorts the collection and removes all but the most recent max
+// Pomodoros in place, returning the number removed.
+func (h *History) Trim(max int) (removed int) {
+	if max < 0 {
+		max = 0
+	}
+
+	n := len(h.Pomodoros)
+	if n <= max {
+		return 0
+	}
+
+	sort.Sort(h)
+	h.Pomodoros = h.Pomodoros[n-max:]
+
+	return n - max
+}
+
+// LongestGap returns the longest stretch between the start and end times in
+// which no Pomodoro was running, along with the time that stretch began.
+// Pomodoros which started before the start time but were still running count
+// towards the window.
+func (h *History) LongestGap(start time.Time, end time.Time) (gap time.Duration, at time.Time) {
+	r := &History{}
+	for _, pomodoro := range h.Pomodoros {
+		if !pomodoro.EndTime().After(start) || pomodoro.StartTime.After(end) {
+			continue
+		}
+		r.Pomodoros = append(r.Pomodoros, pomodoro)
+	}
+	sort.Sort(r)
+
+	cursor := start
+	for _, pomodoro := range r.Pomodoros {
+		if d := pomodoro.StartTime.Sub(cursor); d > gap {
+			gap, at = d, cursor
+		}
+		if t := pomodoro.EndTime(); t.After(cursor) {
+			cursor = t
+		}
+	}
+
+	if d := end.Sub(cursor); d > gap {
+		gap, at = d, cursor
+	}
+
+	return gap, at
+}
+
+// StartIntervals returns the time between the starts of each pair of
+// consecutive Pomodoros, in chronological order. Unlike LongestGap, it
+// measures from start to start, regardless of how long each Pomodoro ran.
+func (h *History) StartIntervals() []time.Duration {
+	sort.Sort(h)
+
+	intervals := []time.Duration{}
+	for i := 1; i < len(h.Pomodoros); i++ {
+		intervals = append(intervals, h.Pomodoros[i].StartTime.Sub(h.Pomodoros[i-1].StartTime))
+	}
+
+	return intervals
+}
+
+// MedianInterval returns the median of the StartIntervals, which is the mean
+// of the middle two if there is an even number of them. It returns 0 if there
+// are fewer than two Pomodoros.
+func (h *History) MedianInterval() time.Duration {
+	intervals := h.StartIntervals()
+	n := len(intervals)
+	if n == 0 {
+		return 0
+	}
+
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i] < intervals[j] })
+
+	if n%2 == 1 {
+		return intervals[n/2]
+	}
+
+	return (intervals[n/2-1] + intervals[n/2]) / 2
+}
+
+// BusiestHour returns the hour of the day (0-23) in the given location in
+// which the most Pomodoros were started, along with how many were started. The
+// earliest hour wins a tie, and the count is 0 for an empty collection.
+func (h *History) BusiestHour(loc *time.Location) (hour int, count int) {
+	var hours [24]int
+	for _, pomodoro := range h.Pomodoros {
+		hours[pomodoro.StartTime.In(loc).Hour()]++
+	}
+
+	for i, n := range hours {
+		if n > count {
+			hour, count = i, n
+		}
+	}
+
+	return hour, count
+}
+
+// HourProbability is the fraction of days on which a Pomodoro was started in
+// an hour of the day.
+type HourProbability struct {
+	Hour        int
+	Probability float64
+}
+
+// TypicalDay returns, for each hour of the day (0-23) in the given location,
+// the fraction of active days on which at least one Pomodoro was started in
+// that hour. Days begin at the DayStartOffset, as in ActiveDays. Every
+// probability is 0 for an empty collection.
+func (h *History) TypicalDay(loc *time.Location) []HourProbability {
+	days := len(h.ActiveDays(loc))
+
+	type dayHour struct {
+		day  string
+		hour int
+	}
+
+	seen := map[dayHour]bool{}
+	var hours [24]int
+	for _, pomodoro := range h.Pomodoros {
+		start := pomodoro.StartTime.In(loc)
+		key := dayHour{start.Add(-h.DayStartOffset).Format(DateFormat), start.Hour()}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		hours[start.Hour()]++
+	}
+
+	typical := make([]HourProbability, len(hours))
+	for i, n := range hours {
+		typical[i].Hour = i
+		if days > 0 {
+			typical[i].Probability = float64(n) / float64(days)
+		}
+	}
+
+	return typical
+}
+
+// WeekBucket is a summary of the Pomodoros started within a week.
+type WeekBucket struct {
+	Start    time.Time
+	Count    int
+	Duration time.Duration
+}
+
+// WeeklyBuckets returns a WeekBucket for every week between the start and end
+// times, including weeks without any Pomodoros. Weeks begin at midnight on the
+// given weekday, in the location of the start time.
+func (h *History) WeeklyBuckets(start time.Time, end time.Time, weekStart time.Weekday) []WeekBucket {
+	var buckets []WeekBucket
+
+	for week := startOfWeek(start, weekStart); week.Before(end); week = week.AddDate(0, 0, 7) {
+		next := week.AddDate(0, 0, 7)
+		bucket := WeekBucket{Start: week}
+
+		for _, pomodoro := range h.Pomodoros {
+			if t := pomodoro.StartTime; t.Before(week) || !t.Before(next) {
+				continue
+			}
+			bucket.Count++
+			bucket.Duration += pomodoro.Duration
+		}
+
+		buckets = append(buckets, bucket)
+	}
+
+	return buckets
+}
+
+func startOfWeek(t time.Time, weekStart time.Weekday) time.Time {
+	y, m, d := t.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	offset := (int(day.Weekday()) - int(weekStart) + 7) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
 // Update replaces a Pomodoro within a History collection in place. If the
 // Pomodoro does not exist in the collection, it is appended and then the
 // collection is sorted.
@@ -111,3 +779,9 @@ func (h *History) Delete(p *Pomodoro) {
 
 	h.Pomodoros = new.Pomodoros
 }
+
+func (h *History) reverse() {
+	for i, j := 0, len(h.Pomodoros)-1; i < j; i, j = i+1, j-1 {
+		h.Pomodoros[i], h.Pomodoros[j] = h.Pomodoros[j], h.Pomodoros[i]
+	}
+}
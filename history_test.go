@@ -1,13 +1,16 @@
 package openpomodoro
 
 import (
+	"bytes"
 	"encoding"
 	"encoding/json"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -25,6 +28,7 @@ var (
 func Test_HistoryInterfaces(t *testing.T) {
 	var _ encoding.TextMarshaler = History{}
 	var _ json.Marshaler = History{}
+	var _ json.Unmarshaler = &History{}
 	var _ sort.Interface = History{}
 }
 
@@ -39,7 +43,7 @@ func TestHistory_MarshalJSON(t *testing.T) {
 	b, err := h.MarshalJSON()
 	assert.Nil(t, err)
 	assert.Equal(t,
-		`{"pomodoros":[{"start_time":"2016-06-14T12:00:00Z","description":"A description","duration":25,"tags":["a","b"]}]}`,
+		`{"pomodoros":[{"start_time":"2016-06-14T12:00:00Z","description":"A description","duration":25,"end_time":"2016-06-14T12:25:00Z","tags":["a","b"]}]}`,
 		string(b))
 }
 
@@ -58,6 +62,43 @@ func TestHistory_MarshalText(t *testing.T) {
 		string(b))
 }
 
+func TestHistory_WriteText(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.Nil(t, many.WriteText(buf))
+
+	text, err := many.MarshalText()
+	assert.Nil(t, err)
+	assert.Equal(t, string(text), buf.String())
+	assert.Equal(t, 3, strings.Count(buf.String(), "\n"))
+
+	buf.Reset()
+	assert.Nil(t, empty.WriteText(buf))
+	assert.Equal(t, "", buf.String())
+}
+
+func Test_IsSorted(t *testing.T) {
+	assert.True(t, empty.IsSorted())
+	assert.True(t, many.IsSorted())
+
+	h := &History{Pomodoros: []*Pomodoro{b, a, c}}
+	assert.False(t, h.IsSorted())
+}
+
+func TestHistory_Equal(t *testing.T) {
+	assert.True(t, empty.Equal(&History{Pomodoros: []*Pomodoro{}}))
+	assert.True(t, many.Equal(many.Copy()))
+	assert.False(t, many.Equal(&one))
+	assert.False(t, many.Equal(&History{Pomodoros: []*Pomodoro{b, a, c}}))
+
+	h := &History{Pomodoros: []*Pomodoro{
+		{StartTime: a.StartTime, Duration: 25 * time.Minute, Tags: []string{"work"}},
+		{StartTime: b.StartTime, Duration: 20 * time.Minute, Notes: "notes"},
+	}}
+	text, err := h.MarshalText()
+	require.Nil(t, err)
+	assert.True(t, h.Equal(parseHistory(text, &DefaultSettings, nil)))
+}
+
 func Test_Latest(t *testing.T) {
 	assert.Nil(t, empty.Latest())
 	assert.Equal(t, b, one.Latest())
@@ -70,10 +111,360 @@ func Test_Count(t *testing.T) {
 	assert.Equal(t, 3, many.Count())
 }
 
+func Test_CompletionRate(t *testing.T) {
+	assert.Equal(t, float64(0), empty.CompletionRate())
+
+	h := &History{Pomodoros: []*Pomodoro{
+		{StartTime: a.StartTime, Duration: 25 * time.Minute},
+		{StartTime: b.StartTime, Duration: 10 * time.Minute, Cancelled: true},
+		{StartTime: b.StartTime.Add(time.Hour), Duration: 25 * time.Minute},
+		{StartTime: c.StartTime},
+	}}
+
+	assert.Equal(t, 0.5, h.CompletionRate())
+	assert.Equal(t, 0.5, h.Date(b.StartTime).CompletionRate())
+}
+
+func Test_Completed(t *testing.T) {
+	h := &History{DayStartOffset: time.Hour, Pomodoros: []*Pomodoro{
+		{StartTime: a.StartTime, Duration: 25 * time.Minute},
+		{StartTime: b.StartTime, Duration: 10 * time.Minute, Cancelled: true},
+		{StartTime: c.StartTime},
+	}}
+
+	completed := h.Completed()
+	assert.Equal(t, []*Pomodoro{h.Pomodoros[0]}, completed.Pomodoros)
+	assert.Equal(t, time.Hour, completed.DayStartOffset)
+
+	abandoned := h.Abandoned()
+	assert.Equal(t, h.Pomodoros[1:], abandoned.Pomodoros)
+	assert.Equal(t, time.Hour, abandoned.DayStartOffset)
+
+	assert.Equal(t, 0, empty.Completed().Count())
+}
+
+func Test_Tags(t *testing.T) {
+	h := &History{Pomodoros: []*Pomodoro{
+		{Tags: []string{"work", "billable"}},
+		{Tags: []string{"work"}},
+		{},
+	}}
+
+	assert.Equal(t, []string{}, empty.Tags())
+	assert.Equal(t, []string{"billable", "work"}, h.Tags())
+	assert.Equal(t, map[string]int{"billable": 1, "work": 2}, h.TagCounts())
+}
+
+func Test_CountWithTag(t *testing.T) {
+	h := &History{DayStartOffset: time.Hour, Pomodoros: []*Pomodoro{
+		{Tags: []string{"work", "email"}},
+		{Tags: []string{"work"}},
+		{Tags: []string{"Work"}},
+		{},
+	}}
+
+	assert.Equal(t, 2, h.CountWithTag("work"))
+	assert.Equal(t, 1, h.CountWithTag("Work"))
+	assert.Equal(t, 0, h.CountWithTag("play"))
+
+	tagged := h.WithTag("email")
+	require.Equal(t, 1, tagged.Count())
+	assert.Equal(t, h.Pomodoros[0], tagged.Pomodoros[0])
+	assert.Equal(t, time.Hour, tagged.DayStartOffset)
+}
+
+func Test_TagCooccurrence(t *testing.T) {
+	h := &History{Pomodoros: []*Pomodoro{
+		{Tags: []string{"work", "billable", "work"}},
+		{Tags: []string{"work", "billable", "meeting"}},
+		{Tags: []string{"home"}},
+		{},
+	}}
+
+	assert.Equal(t, map[string]map[string]int{}, empty.TagCooccurrence())
+	assert.Equal(t, map[string]map[string]int{
+		"work":     {"billable": 2, "meeting": 1},
+		"billable": {"work": 2, "meeting": 1},
+		"meeting":  {"work": 1, "billable": 1},
+	}, h.TagCooccurrence())
+}
+
+func Test_TopDescriptions(t *testing.T) {
+	h := &History{Pomodoros: []*Pomodoro{
+		{StartTime: a.StartTime, Description: "Writing"},
+		{StartTime: b.StartTime, Description: "writing "},
+		{StartTime: c.StartTime, Description: "reading"},
+		{StartTime: c.StartTime.Add(time.Hour), Description: "email"},
+		{StartTime: c.StartTime.Add(2 * time.Hour)},
+	}}
+
+	assert.Equal(t, []DescriptionCount{}, empty.TopDescriptions(3))
+	assert.Equal(t, []DescriptionCount{
+		{Description: "Writing", Count: 2},
+		{Description: "email", Count: 1},
+	}, h.TopDescriptions(2))
+	assert.Len(t, h.TopDescriptions(0), 3)
+}
+
 func Test_Date(t *testing.T) {
 	assert.Equal(t, &one, many.Date(b.StartTime))
 }
 
+func Test_Date_nextMidnight(t *testing.T) {
+	midnight := &Pomodoro{StartTime: time.Date(2016, 06, 15, 0, 0, 0, 0, time.UTC)}
+	h := &History{Pomodoros: []*Pomodoro{b, midnight}}
+
+	assert.Equal(t, &History{Pomodoros: []*Pomodoro{b}}, h.Date(b.StartTime))
+	assert.Equal(t, &History{Pomodoros: []*Pomodoro{midnight}}, h.Date(midnight.StartTime))
+}
+
+func Test_Date_dayStartOffset(t *testing.T) {
+	late := &Pomodoro{StartTime: time.Date(2016, 06, 15, 1, 0, 0, 0, time.UTC)}
+	h := &History{Pomodoros: []*Pomodoro{b, late}, DayStartOffset: 4 * time.Hour}
+
+	assert.Equal(t,
+		&History{Pomodoros: []*Pomodoro{b, late}, DayStartOffset: 4 * time.Hour},
+		h.Date(b.StartTime))
+}
+
+func Test_DateWithOffset(t *testing.T) {
+	late := &Pomodoro{StartTime: time.Date(2016, 06, 15, 1, 0, 0, 0, time.UTC)}
+	early := &Pomodoro{StartTime: time.Date(2016, 06, 15, 4, 0, 0, 0, time.UTC)}
+	h := &History{Pomodoros: []*Pomodoro{b, late, early}}
+
+	assert.Equal(t,
+		&History{Pomodoros: []*Pomodoro{b, late}},
+		h.DateWithOffset(b.StartTime, 4*time.Hour))
+	assert.Equal(t,
+		&History{Pomodoros: []*Pomodoro{early}},
+		h.DateWithOffset(early.StartTime, 4*time.Hour))
+	assert.Equal(t,
+		&History{Pomodoros: []*Pomodoro{late, early}},
+		h.DateWithOffset(early.StartTime, 0))
+}
+
+func Test_IndexByDate(t *testing.T) {
+	late := &Pomodoro{StartTime: time.Date(2016, 06, 15, 1, 0, 0, 0, time.UTC)}
+	h := &History{Pomodoros: []*Pomodoro{a, b, late, c}, DayStartOffset: 4 * time.Hour}
+
+	index := h.IndexByDate(time.UTC)
+	for _, p := range h.Pomodoros {
+		assert.Equal(t, h.Date(p.StartTime), index.Day(p.StartTime))
+	}
+	assert.Equal(t, &History{DayStartOffset: 4 * time.Hour}, index.Day(a.StartTime.AddDate(0, 0, -1)))
+
+	h.Update(&Pomodoro{StartTime: a.StartTime.AddDate(0, 0, -1)})
+	assert.Equal(t, 0, index.Day(a.StartTime.AddDate(0, 0, -1)).Count())
+}
+
+func Test_StartOfDay(t *testing.T) {
+	h := &History{}
+	assert.Equal(t,
+		time.Date(2016, 06, 14, 0, 0, 0, 0, time.UTC),
+		h.StartOfDay(time.Date(2016, 06, 14, 1, 0, 0, 0, time.UTC)))
+
+	h.DayStartOffset = 4 * time.Hour
+	assert.Equal(t,
+		time.Date(2016, 06, 13, 4, 0, 0, 0, time.UTC),
+		h.StartOfDay(time.Date(2016, 06, 14, 1, 0, 0, 0, time.UTC)))
+	assert.Equal(t,
+		time.Date(2016, 06, 14, 4, 0, 0, 0, time.UTC),
+		h.StartOfDay(time.Date(2016, 06, 14, 4, 0, 0, 0, time.UTC)))
+}
+
+func Test_DurationPercentile(t *testing.T) {
+	h := &History{}
+	for _, minutes := range []int{25, 10, 20, 30, 15} {
+		h.Pomodoros = append(h.Pomodoros, &Pomodoro{Duration: time.Duration(minutes) * time.Minute})
+	}
+	h.Pomodoros = append(h.Pomodoros,
+		&Pomodoro{Duration: 2 * time.Minute, Cancelled: true},
+		&Pomodoro{},
+	)
+
+	assert.Equal(t, 20*time.Minute, h.DurationPercentile(0.5))
+	assert.Equal(t, 10*time.Minute, h.DurationPercentile(0))
+	assert.Equal(t, 30*time.Minute, h.DurationPercentile(1))
+	assert.Equal(t, 12*time.Minute+30*time.Second, h.DurationPercentile(0.125))
+	assert.Equal(t, 10*time.Minute, h.DurationPercentile(-1))
+	assert.Equal(t, 30*time.Minute, h.DurationPercentile(2))
+
+	assert.Equal(t, time.Duration(0), empty.DurationPercentile(0.5))
+}
+
+func Test_Compact(t *testing.T) {
+	start := time.Date(2016, 06, 14, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int, duration int, description string, tags ...string) *Pomodoro {
+		return &Pomodoro{
+			StartTime:   start.Add(time.Duration(minutes) * time.Minute),
+			Duration:    time.Duration(duration) * time.Minute,
+			Description: description,
+			Tags:        tags,
+		}
+	}
+
+	h := &History{Pomodoros: []*Pomodoro{
+		at(12, 10, "writing", "work"),
+		at(0, 10, "writing", "work"),
+		at(24, 5, "writing", "work"),
+		at(30, 10, "writing", "home"),
+		at(40, 10, "reading", "home"),
+		at(60, 10, "reading", "home"),
+	}}
+	h.Pomodoros = append(h.Pomodoros, &Pomodoro{
+		StartTime: start.Add(70 * time.Minute), Duration: 5 * time.Minute,
+		Description: "reading", Tags: []string{"home"}, Cancelled: true,
+	})
+
+	compacted := h.Compact(5 * time.Minute)
+	assert.Equal(t, []*Pomodoro{
+		at(0, 25, "writing", "work"),
+		at(30, 10, "writing", "home"),
+		at(40, 10, "reading", "home"),
+		at(60, 10, "reading", "home"),
+		h.Pomodoros[6],
+	}, compacted.Pomodoros)
+
+	assert.Equal(t, 4, h.Compact(time.Hour).Count())
+
+	assert.Equal(t, 7, h.Count())
+	assert.Equal(t, 10*time.Minute, h.Pomodoros[1].Duration)
+}
+
+func Test_InferDurations(t *testing.T) {
+	start := time.Date(2016, 06, 14, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) *Pomodoro {
+		return &Pomodoro{StartTime: start.Add(time.Duration(minutes) * time.Minute)}
+	}
+
+	h := &History{Pomodoros: []*Pomodoro{at(20), at(0), at(70), at(60)}}
+
+	inferred := h.InferDurations(25 * time.Minute)
+	var durations []time.Duration
+	for _, p := range inferred.Pomodoros {
+		durations = append(durations, p.Duration)
+	}
+	assert.Equal(t, []time.Duration{
+		20 * time.Minute,
+		25 * time.Minute,
+		10 * time.Minute,
+		25 * time.Minute,
+	}, durations)
+	assert.True(t, inferred.IsSorted())
+
+	assert.Equal(t, time.Duration(0), h.Pomodoros[0].Duration)
+	assert.Equal(t, 0, empty.InferDurations(25*time.Minute).Count())
+}
+
+func Test_WithDefaultDurations(t *testing.T) {
+	start := time.Date(2016, 06, 14, 12, 0, 0, 0, time.UTC)
+	h := &History{DayStartOffset: time.Hour, Pomodoros: []*Pomodoro{
+		{StartTime: start},
+		{StartTime: start.Add(10 * time.Minute), Duration: 20 * time.Minute},
+		{StartTime: start.Add(time.Hour), Cancelled: true},
+	}}
+
+	filled := h.WithDefaultDurations(25 * time.Minute)
+	require.Equal(t, 3, filled.Count())
+	assert.Equal(t, 25*time.Minute, filled.Pomodoros[0].Duration)
+	assert.Equal(t, 20*time.Minute, filled.Pomodoros[1].Duration)
+	assert.Equal(t, time.Duration(0), filled.Pomodoros[2].Duration)
+	assert.Equal(t, time.Hour, filled.DayStartOffset)
+	assert.Equal(t, 45*time.Minute, filled.Duration())
+
+	assert.Equal(t, time.Duration(0), h.Pomodoros[0].Duration)
+}
+
+func Test_ActiveDays(t *testing.T) {
+	assert.Equal(t, []time.Time{}, empty.ActiveDays(time.UTC))
+
+	assert.Equal(t, []time.Time{
+		time.Date(2016, 06, 13, 0, 0, 0, 0, time.UTC),
+		time.Date(2016, 06, 14, 0, 0, 0, 0, time.UTC),
+		time.Date(2016, 06, 15, 0, 0, 0, 0, time.UTC),
+	}, (&History{Pomodoros: []*Pomodoro{c, a, b, a}}).ActiveDays(time.UTC))
+
+	ny, err := time.LoadLocation("America/New_York")
+	require.Nil(t, err)
+
+	// Clocks went forward at 2am on March 13, 2016, and back at 2am on
+	// November 6, 2016.
+	h := &History{Pomodoros: []*Pomodoro{
+		{StartTime: time.Date(2016, 03, 13, 1, 30, 0, 0, ny)},
+		{StartTime: time.Date(2016, 03, 13, 23, 30, 0, 0, ny)},
+		{StartTime: time.Date(2016, 11, 06, 23, 30, 0, 0, ny)},
+		{StartTime: time.Date(2016, 11, 06, 1, 30, 0, 0, ny).Add(time.Hour)},
+		{StartTime: time.Date(2016, 11, 07, 0, 0, 0, 0, ny)},
+	}}
+
+	assert.Equal(t, []time.Time{
+		time.Date(2016, 03, 13, 0, 0, 0, 0, ny),
+		time.Date(2016, 11, 06, 0, 0, 0, 0, ny),
+		time.Date(2016, 11, 07, 0, 0, 0, 0, ny),
+	}, h.ActiveDays(ny))
+
+	assert.Equal(t, []time.Time{
+		time.Date(2016, 03, 13, 0, 0, 0, 0, time.UTC),
+		time.Date(2016, 03, 14, 0, 0, 0, 0, time.UTC),
+		time.Date(2016, 11, 06, 0, 0, 0, 0, time.UTC),
+		time.Date(2016, 11, 07, 0, 0, 0, 0, time.UTC),
+	}, h.ActiveDays(time.UTC))
+
+	h.DayStartOffset = 4 * time.Hour
+	assert.Equal(t, []time.Time{
+		time.Date(2016, 03, 12, 0, 0, 0, 0, ny),
+		time.Date(2016, 03, 13, 0, 0, 0, 0, ny),
+		time.Date(2016, 11, 05, 0, 0, 0, 0, ny),
+		time.Date(2016, 11, 06, 0, 0, 0, 0, ny),
+	}, h.ActiveDays(ny))
+}
+
+func Test_GoalMetDays(t *testing.T) {
+	edt := time.FixedZone("EDT", -4*60*60)
+	h := &History{Pomodoros: []*Pomodoro{
+		{StartTime: time.Date(2016, 06, 13, 9, 0, 0, 0, edt), Duration: 25 * time.Minute},
+		{StartTime: time.Date(2016, 06, 13, 10, 0, 0, 0, edt), Duration: 25 * time.Minute},
+		{StartTime: time.Date(2016, 06, 14, 9, 0, 0, 0, edt), Duration: 25 * time.Minute},
+		{StartTime: time.Date(2016, 06, 14, 10, 0, 0, 0, edt), Duration: 5 * time.Minute, Cancelled: true},
+		{StartTime: time.Date(2016, 06, 15, 9, 0, 0, 0, edt), Duration: 25 * time.Minute},
+		{StartTime: time.Date(2016, 06, 15, 22, 0, 0, 0, edt), Duration: 25 * time.Minute},
+	}}
+
+	start := time.Date(2016, 06, 13, 0, 0, 0, 0, edt)
+	end := time.Date(2016, 06, 16, 0, 0, 0, 0, edt)
+
+	assert.Equal(t, 2, h.GoalMetDays(2, start, end, edt))
+	assert.Equal(t, 3, h.GoalMetDays(1, start, end, edt))
+	assert.Equal(t, 0, h.GoalMetDays(0, start, end, edt))
+
+	assert.Equal(t, 1, h.GoalMetDays(2, start, end, time.UTC))
+
+	h.DayStartOffset = 3 * time.Hour
+	assert.Equal(t, 2, h.GoalMetDays(2, start, end, time.UTC))
+}
+
+func Test_AllocateAcrossDays(t *testing.T) {
+	edt := time.FixedZone("EDT", -4*60*60)
+	h := &History{Pomodoros: []*Pomodoro{
+		{StartTime: time.Date(2016, 06, 13, 9, 0, 0, 0, edt), Duration: 25 * time.Minute},
+		{StartTime: time.Date(2016, 06, 13, 23, 50, 0, 0, edt), Duration: 25 * time.Minute},
+		{StartTime: time.Date(2016, 06, 14, 23, 35, 0, 0, edt), Duration: 25 * time.Minute},
+	}}
+
+	assert.Equal(t, map[string]time.Duration{
+		"2016-06-13": 35 * time.Minute,
+		"2016-06-14": 40 * time.Minute,
+	}, h.AllocateAcrossDays(edt))
+
+	assert.Equal(t, map[string]time.Duration{
+		"2016-06-13": 25 * time.Minute,
+		"2016-06-14": 25 * time.Minute,
+		"2016-06-15": 25 * time.Minute,
+	}, h.AllocateAcrossDays(time.UTC))
+
+	assert.Equal(t, map[string]time.Duration{}, empty.AllocateAcrossDays(time.UTC))
+}
+
 func Test_Range(t *testing.T) {
 	start := time.Date(2016, 06, 14, 0, 0, 0, 0, time.UTC)
 	end := time.Date(2016, 06, 15, 0, 0, 0, 0, time.UTC)
@@ -83,6 +474,169 @@ func Test_Range(t *testing.T) {
 	assert.Equal(t, 1, many.Range(start, end).Count())
 }
 
+func Test_Since(t *testing.T) {
+	assert.Equal(t, 0, empty.Since(b.StartTime).Count())
+	assert.Equal(t, 1, one.Since(b.StartTime).Count())
+	assert.Equal(t, 2, many.Since(b.StartTime).Count())
+	assert.Equal(t, 0, many.Since(c.StartTime.Add(time.Second)).Count())
+}
+
+func Test_Duration(t *testing.T) {
+	h := &History{Pomodoros: []*Pomodoro{
+		{Duration: 25 * time.Minute},
+		{Duration: 20 * time.Minute},
+	}}
+
+	assert.Equal(t, time.Duration(0), empty.Duration())
+	assert.Equal(t, 45*time.Minute, h.Duration())
+}
+
+func Test_TotalDurationRounded(t *testing.T) {
+	total := func(minutes ...float64) *History {
+		h := &History{}
+		for _, m := range minutes {
+			h.Pomodoros = append(h.Pomodoros, &Pomodoro{Duration: time.Duration(m * float64(time.Minute))})
+		}
+		return h
+	}
+
+	five := 5 * time.Minute
+	assert.Equal(t, 45*time.Minute, total(25, 20).TotalDurationRounded(five))
+	assert.Equal(t, 45*time.Minute, total(25, 22).TotalDurationRounded(five))
+	assert.Equal(t, 50*time.Minute, total(25, 22.5).TotalDurationRounded(five))
+	assert.Equal(t, 50*time.Minute, total(25, 23).TotalDurationRounded(five))
+	assert.Equal(t, 2*time.Hour+15*time.Minute, total(60, 60, 13).TotalDurationRounded(five))
+	assert.Equal(t, 47*time.Minute+30*time.Second, total(25, 22.5).TotalDurationRounded(0))
+	assert.Equal(t, time.Duration(0), empty.TotalDurationRounded(five))
+}
+
+func Test_Trim(t *testing.T) {
+	history := &History{Pomodoros: []*Pomodoro{c, a, b}}
+
+	assert.Equal(t, 0, history.Trim(3))
+	assert.Equal(t, 3, history.Count())
+
+	assert.Equal(t, 1, history.Trim(2))
+	assert.Equal(t, &History{Pomodoros: []*Pomodoro{b, c}}, history)
+
+	assert.Equal(t, 2, history.Trim(0))
+	assert.Equal(t, 0, history.Count())
+}
+
+func Test_LongestGap(t *testing.T) {
+	start := time.Date(2016, 06, 14, 9, 0, 0, 0, time.UTC)
+	end := time.Date(2016, 06, 14, 17, 0, 0, 0, time.UTC)
+
+	gap, at := empty.LongestGap(start, end)
+	assert.Equal(t, 8*time.Hour, gap)
+	assert.Equal(t, start, at)
+
+	h := &History{Pomodoros: []*Pomodoro{
+		{StartTime: time.Date(2016, 06, 14, 13, 0, 0, 0, time.UTC), Duration: 25 * time.Minute},
+		{StartTime: time.Date(2016, 06, 14, 10, 0, 0, 0, time.UTC), Duration: 25 * time.Minute},
+		{StartTime: time.Date(2016, 06, 14, 16, 0, 0, 0, time.UTC), Duration: 25 * time.Minute},
+	}}
+
+	gap, at = h.LongestGap(start, end)
+	assert.Equal(t, 2*time.Hour+35*time.Minute, gap)
+	assert.Equal(t, time.Date(2016, 06, 14, 10, 25, 0, 0, time.UTC), at)
+
+	running := &History{Pomodoros: []*Pomodoro{
+		{StartTime: time.Date(2016, 06, 14, 8, 50, 0, 0, time.UTC), Duration: time.Hour},
+	}}
+
+	gap, at = running.LongestGap(start, start.Add(time.Hour))
+	assert.Equal(t, 10*time.Minute, gap)
+	assert.Equal(t, time.Date(2016, 06, 14, 9, 50, 0, 0, time.UTC), at)
+}
+
+func Test_StartIntervals(t *testing.T) {
+	assert.Equal(t, []time.Duration{}, empty.StartIntervals())
+	assert.Equal(t, time.Duration(0), one.MedianInterval())
+
+	start := time.Date(2016, 06, 14, 9, 0, 0, 0, time.UTC)
+	h := &History{Pomodoros: []*Pomodoro{
+		{StartTime: start.Add(30 * time.Minute)},
+		{StartTime: start},
+		{StartTime: start.Add(2 * time.Hour)},
+	}}
+
+	assert.Equal(t, []time.Duration{30 * time.Minute, 90 * time.Minute}, h.StartIntervals())
+	assert.Equal(t, time.Hour, h.MedianInterval())
+
+	h.Pomodoros = append(h.Pomodoros, &Pomodoro{StartTime: start.Add(2*time.Hour + 40*time.Minute)})
+	assert.Equal(t, 40*time.Minute, h.MedianInterval())
+}
+
+func Test_BusiestHour(t *testing.T) {
+	hour, count := empty.BusiestHour(time.UTC)
+	assert.Equal(t, 0, count)
+
+	h := &History{Pomodoros: []*Pomodoro{
+		{StartTime: time.Date(2016, 06, 14, 9, 0, 0, 0, time.UTC)},
+		{StartTime: time.Date(2016, 06, 14, 14, 0, 0, 0, time.UTC)},
+		{StartTime: time.Date(2016, 06, 15, 14, 30, 0, 0, time.UTC)},
+	}}
+
+	hour, count = h.BusiestHour(time.UTC)
+	assert.Equal(t, 14, hour)
+	assert.Equal(t, 2, count)
+
+	hour, _ = h.BusiestHour(time.FixedZone("EDT", -4*60*60))
+	assert.Equal(t, 10, hour)
+}
+
+func Test_TypicalDay(t *testing.T) {
+	typical := empty.TypicalDay(time.UTC)
+	require.Len(t, typical, 24)
+	assert.Equal(t, HourProbability{Hour: 9}, typical[9])
+
+	h := &History{Pomodoros: []*Pomodoro{
+		{StartTime: time.Date(2016, 06, 13, 9, 0, 0, 0, time.UTC)},
+		{StartTime: time.Date(2016, 06, 13, 9, 30, 0, 0, time.UTC)},
+		{StartTime: time.Date(2016, 06, 13, 14, 0, 0, 0, time.UTC)},
+		{StartTime: time.Date(2016, 06, 14, 9, 15, 0, 0, time.UTC)},
+		{StartTime: time.Date(2016, 06, 15, 9, 45, 0, 0, time.UTC)},
+		{StartTime: time.Date(2016, 06, 16, 16, 0, 0, 0, time.UTC)},
+	}}
+
+	typical = h.TypicalDay(time.UTC)
+	require.Len(t, typical, 24)
+	assert.Equal(t, HourProbability{Hour: 9, Probability: 0.75}, typical[9])
+	assert.Equal(t, HourProbability{Hour: 14, Probability: 0.25}, typical[14])
+	assert.Equal(t, HourProbability{Hour: 16, Probability: 0.25}, typical[16])
+	assert.Equal(t, HourProbability{Hour: 10}, typical[10])
+
+	typical = h.TypicalDay(time.FixedZone("EDT", -4*60*60))
+	assert.Equal(t, 0.75, typical[5].Probability)
+}
+
+func Test_WeeklyBuckets(t *testing.T) {
+	h := &History{Pomodoros: []*Pomodoro{
+		{StartTime: time.Date(2015, 12, 28, 9, 0, 0, 0, time.UTC), Duration: 25 * time.Minute},
+		{StartTime: time.Date(2016, 01, 03, 23, 0, 0, 0, time.UTC), Duration: 25 * time.Minute},
+		{StartTime: time.Date(2016, 01, 04, 0, 0, 0, 0, time.UTC), Duration: 20 * time.Minute},
+		{StartTime: time.Date(2016, 01, 20, 9, 0, 0, 0, time.UTC), Duration: 25 * time.Minute},
+	}}
+
+	start := time.Date(2015, 12, 30, 12, 0, 0, 0, time.UTC)
+	end := time.Date(2016, 01, 21, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, []WeekBucket{
+		{Start: time.Date(2015, 12, 28, 0, 0, 0, 0, time.UTC), Count: 2, Duration: 50 * time.Minute},
+		{Start: time.Date(2016, 01, 04, 0, 0, 0, 0, time.UTC), Count: 1, Duration: 20 * time.Minute},
+		{Start: time.Date(2016, 01, 11, 0, 0, 0, 0, time.UTC), Count: 0},
+		{Start: time.Date(2016, 01, 18, 0, 0, 0, 0, time.UTC), Count: 1, Duration: 25 * time.Minute},
+	}, h.WeeklyBuckets(start, end, time.Monday))
+
+	buckets := h.WeeklyBuckets(start, end, time.Sunday)
+	assert.Equal(t, time.Date(2015, 12, 27, 0, 0, 0, 0, time.UTC), buckets[0].Start)
+	assert.Equal(t, 1, buckets[0].Count)
+	assert.Equal(t, 2, buckets[1].Count)
+
+	assert.Nil(t, empty.WeeklyBuckets(end, start, time.Monday))
+}
+
 func Test_Update(t *testing.T) {
 	history := &History{}
 
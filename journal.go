@@ -0,0 +1,108 @@
+package openpomodoro
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/justincampbell/go-logfmt"
+)
+
+// ErrEmptyNote is returned by Journal when the note is empty.
+var ErrEmptyNote = errors.New("journal note is empty")
+
+// JournalEntry is a timestamped note which is not tied to a Pomodoro.
+type JournalEntry struct {
+	// Time is when the entry was written.
+	Time time.Time `json:"time"`
+
+	// Message is the text of the entry.
+	Message string `logfmt:"message" json:"message"`
+}
+
+// MarshalText marshals the entry's timestamp and message into a text string.
+func (e JournalEntry) MarshalText() ([]byte, error) {
+	timestamp := []byte(e.Time.Format(TimeFormat))
+	attributes, err := logfmt.Encode(e)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.Join([][]byte{timestamp, attributes}, charSpace), nil
+}
+
+// UnmarshalText updates an entry's timestamp and message from a byte string.
+func (e *JournalEntry) UnmarshalText(b []byte) error {
+	parts := bytes.SplitN(bytes.TrimSpace(b), charSpace, 2)
+
+	var attributes []byte
+	if len(parts) == 2 {
+		attributes = parts[1]
+	}
+
+	t, attributes, err := parseTimestamp(parts[0], attributes)
+	if err != nil {
+		return err
+	}
+
+	e.Time = t
+	return logfmt.Unmarshal(attributes, e)
+}
+
+// Journal appends a note with the current time to the `journal` file. Newlines
+// in the note are replaced with spaces.
+func (c *Client) Journal(note string) error {
+	note = strings.TrimSpace(strings.Replace(note, "\n", " ", -1))
+	if note == "" {
+		return ErrEmptyNote
+	}
+
+	b, err := JournalEntry{Time: c.now(), Message: note}.MarshalText()
+	if err != nil {
+		return err
+	}
+
+	if err := c.ensureDirectory(); err != nil {
+		return err
+	}
+
+	return c.appendFile(c.JournalPath(), append(b, charNewline...))
+}
+
+// JournalEntries returns the entries in the `journal` file written on the
+// given date, where the day begins at the DayStartOffset setting.
+func (c *Client) JournalEntries(date time.Time) ([]JournalEntry, error) {
+	s, err := c.readSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := readRaw(c.JournalPath())
+	if err != nil {
+		return nil, err
+	}
+
+	y, m, d := date.Date()
+	start := time.Date(y, m, d, 0, 0, 0, 0, date.Location()).Add(s.DayStartOffset)
+	end := start.AddDate(0, 0, 1)
+
+	entries := []JournalEntry{}
+	for _, line := range bytes.Split(b, charNewline) {
+		if bytesAllWhitespace(line) {
+			continue
+		}
+
+		var e JournalEntry
+		if err := e.UnmarshalText(line); err != nil {
+			debug("skipping journal line %q: %s", line, err)
+			continue
+		}
+
+		if !e.Time.Before(start) && e.Time.Before(end) {
+			entries = append(entries, e)
+		}
+	}
+
+	return entries, nil
+}
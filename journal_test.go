@@ -0,0 +1,42 @@
+package openpomodoro
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Journal(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	entries, err := c.JournalEntries(fakeTime())
+	require.Nil(t, err)
+	assert.Empty(t, entries)
+
+	assert.Equal(t, ErrEmptyNote, c.Journal(" \n "))
+
+	require.Nil(t, c.Journal("slow start,\nthen \"focused\""))
+	timeTravel(24*time.Hour)(t, c, "")
+	require.Nil(t, c.Journal("next day"))
+
+	b, err := ioutil.ReadFile(c.JournalPath())
+	require.Nil(t, err)
+	assert.Equal(t, `2016-06-14T12:34:56-04:00 message="slow start, then \"focused\""
+2016-06-15T12:34:56-04:00 message="next day"
+`, string(b))
+
+	entries, err = c.JournalEntries(fakeTime())
+	require.Nil(t, err)
+	require.Len(t, entries, 1)
+	assert.True(t, fakeTime().Equal(entries[0].Time))
+	assert.Equal(t, `slow start, then "focused"`, entries[0].Message)
+
+	entries, err = c.JournalEntries(fakeTime().AddDate(0, 0, 1))
+	require.Nil(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "next day", entries[0].Message)
+}
@@ -0,0 +1,161 @@
+package openpomodoro
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// MergeStrategy decides which Pomodoro is kept when merging histories which
+// both have one with the same start time.
+type MergeStrategy string
+
+const (
+	// PreferExisting keeps the existing Pomodoro.
+	PreferExisting MergeStrategy = "existing"
+
+	// PreferIncoming replaces the existing Pomodoro with the incoming one.
+	PreferIncoming MergeStrategy = "incoming"
+
+	// PreferLonger keeps whichever Pomodoro has the longer duration, and the
+	// existing one if they are the same.
+	PreferLonger MergeStrategy = "longer"
+)
+
+// Merge adds copies of the Pomodoros from another History collection in place.
+// Pomodoros which match an existing one are resolved with the strategy, and
+// counted as updated only if they replace it with something different.
+func (h *History) Merge(o *History, strategy MergeStrategy) (added, updated int, err error) {
+	switch strategy {
+	case PreferExisting, PreferIncoming, PreferLonger:
+	default:
+		return 0, 0, fmt.Errorf("unknown merge strategy %q", strategy)
+	}
+
+	for _, incoming := range o.Pomodoros {
+		i := h.index(incoming)
+		if i < 0 {
+			h.Pomodoros = append(h.Pomodoros, incoming.Copy())
+			added++
+			continue
+		}
+
+		existing := h.Pomodoros[i]
+		replace := false
+		switch strategy {
+		case PreferIncoming:
+			replace = existing.String() != incoming.String()
+		case PreferLonger:
+			replace = incoming.Duration > existing.Duration
+		}
+
+		if replace {
+			h.Pomodoros[i] = incoming.Copy()
+			updated++
+		}
+	}
+
+	return added, updated, nil
+}
+
+// index returns the position of the Pomodoro matching p, or -1.
+func (h *History) index(p *Pomodoro) int {
+	for i, needle := range h.Pomodoros {
+		if needle.Matches(p) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Import merges the Pomodoros from another `history` file into this Client's
+// `history` file using the strategy, and returns how many were added and
+// updated. The file is only rewritten if anything changed.
+func (c *Client) Import(path string, strategy MergeStrategy) (added, updated int, err error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	s, err := c.readSettings()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	history, err := c.History()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	added, updated, err = history.Merge(parseHistory(b, s, c.Clock), strategy)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if added+updated == 0 {
+		return 0, 0, nil
+	}
+
+	if err := c.ensureDirectory(); err != nil {
+		return 0, 0, err
+	}
+
+	if err := c.writeHistory(history); err != nil {
+		return 0, 0, err
+	}
+
+	return added, updated, nil
+}
+
+// ImportJSON reads a History from r as written by ExportJSON, and either
+// merges it into the `history` file keeping existing Pomodoros where they
+// match, or replaces the file with it. It returns how many Pomodoros were
+// imported, which for a merge is how many were added. Nothing is changed if r
+// cannot be read.
+func (c *Client) ImportJSON(r io.Reader, merge bool) (int, error) {
+	incoming := &History{}
+	if err := json.NewDecoder(r).Decode(incoming); err != nil {
+		return 0, err
+	}
+
+	s, err := c.Settings()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, p := range incoming.Pomodoros {
+		p.Rounding = s.Rounding
+		p.TagSeparator = s.TagSeparator
+	}
+
+	history := incoming
+	n := incoming.Count()
+
+	if merge {
+		history, err = c.History()
+		if err != nil {
+			return 0, err
+		}
+
+		n, _, err = history.Merge(incoming, PreferExisting)
+		if err != nil {
+			return 0, err
+		}
+
+		if n == 0 {
+			return 0, nil
+		}
+	}
+
+	if err := c.ensureDirectory(); err != nil {
+		return 0, err
+	}
+
+	if err := c.writeHistory(history); err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
@@ -0,0 +1,136 @@
+package openpomodoro
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory_Merge(t *testing.T) {
+	start := time.Date(2016, 06, 14, 12, 0, 0, 0, time.UTC)
+
+	existing := func() *History {
+		return &History{Pomodoros: []*Pomodoro{
+			{StartTime: start, Duration: 20 * time.Minute, Description: "mine"},
+		}}
+	}
+	incoming := &History{Pomodoros: []*Pomodoro{
+		{StartTime: start, Duration: 25 * time.Minute, Description: "theirs"},
+		{StartTime: start.Add(time.Hour), Duration: 25 * time.Minute},
+	}}
+
+	h := existing()
+	added, updated, err := h.Merge(incoming, PreferExisting)
+	require.Nil(t, err)
+	assert.Equal(t, 1, added)
+	assert.Equal(t, 0, updated)
+	assert.Equal(t, "mine", h.Pomodoros[0].Description)
+	assert.Equal(t, 2, h.Count())
+
+	h = existing()
+	added, updated, err = h.Merge(incoming, PreferIncoming)
+	require.Nil(t, err)
+	assert.Equal(t, 1, added)
+	assert.Equal(t, 1, updated)
+	assert.Equal(t, "theirs", h.Pomodoros[0].Description)
+
+	h = existing()
+	h.Pomodoros[0].Duration = 30 * time.Minute
+	_, updated, err = h.Merge(incoming, PreferLonger)
+	require.Nil(t, err)
+	assert.Equal(t, 0, updated)
+	assert.Equal(t, "mine", h.Pomodoros[0].Description)
+
+	h = existing()
+	_, updated, err = h.Merge(incoming, PreferLonger)
+	require.Nil(t, err)
+	assert.Equal(t, 1, updated)
+	assert.Equal(t, "theirs", h.Pomodoros[0].Description)
+
+	h = existing()
+	_, updated, err = h.Merge(existing(), PreferIncoming)
+	require.Nil(t, err)
+	assert.Equal(t, 0, updated)
+
+	_, _, err = h.Merge(incoming, "newest")
+	assert.NotNil(t, err)
+}
+
+func Test_Import(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	require.Nil(t, c.Log(&Pomodoro{Description: "mine"}, fakeTime().Add(-time.Hour), 20*time.Minute))
+
+	other := filepath.Join(c.Directory, "other")
+	require.Nil(t, ioutil.WriteFile(other, []byte(`2016-06-14T11:34:56-04:00 description=theirs duration=25
+2016-06-13T11:34:56-04:00 duration=25
+`), FilePerm))
+
+	added, updated, err := c.Import(other, PreferLonger)
+	require.Nil(t, err)
+	assert.Equal(t, 1, added)
+	assert.Equal(t, 1, updated)
+
+	h, err := c.History()
+	require.Nil(t, err)
+	require.Equal(t, 2, h.Count())
+	assert.True(t, h.IsSorted())
+	assert.Equal(t, "theirs", h.Latest().Description)
+
+	added, updated, err = c.Import(other, PreferLonger)
+	require.Nil(t, err)
+	assert.Equal(t, 0, added)
+	assert.Equal(t, 0, updated)
+
+	_, _, err = c.Import(filepath.Join(c.Directory, "missing"), PreferLonger)
+	assert.NotNil(t, err)
+}
+
+func TestClient_ImportJSON(t *testing.T) {
+	source, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+	require.Nil(t, source.Log(&Pomodoro{Description: "backup", Tags: []string{"a", "b"}}, fakeTime().Add(-2*time.Hour), 25*time.Minute))
+	require.Nil(t, source.Log(&Pomodoro{Description: "shared"}, fakeTime().Add(-time.Hour), 25*time.Minute))
+
+	backup := &bytes.Buffer{}
+	require.Nil(t, source.ExportJSON(backup))
+
+	c, err := NewClient(fixture(""))
+	require.Nil(t, err)
+	require.Nil(t, c.Log(&Pomodoro{Description: "mine"}, fakeTime().Add(-time.Hour), 20*time.Minute))
+
+	n, err := c.ImportJSON(bytes.NewReader(backup.Bytes()), true)
+	require.Nil(t, err)
+	assert.Equal(t, 1, n)
+
+	h, err := c.History()
+	require.Nil(t, err)
+	require.Equal(t, 2, h.Count())
+	assert.Equal(t, "mine", h.Latest().Description)
+	assert.Equal(t, []string{"a", "b"}, h.Pomodoros[0].Tags)
+
+	n, err = c.ImportJSON(bytes.NewReader(backup.Bytes()), true)
+	require.Nil(t, err)
+	assert.Equal(t, 0, n)
+
+	n, err = c.ImportJSON(bytes.NewReader(backup.Bytes()), false)
+	require.Nil(t, err)
+	assert.Equal(t, 2, n)
+
+	h, err = c.History()
+	require.Nil(t, err)
+	expected, err := source.History()
+	require.Nil(t, err)
+	assert.True(t, expected.Equal(h))
+
+	_, err = c.ImportJSON(strings.NewReader("{"), false)
+	assert.NotNil(t, err)
+	assertHistoryLength(2)(t, c, "")
+}
@@ -0,0 +1,49 @@
+package openpomodoro
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Metrics returns the current state in the Prometheus text exposition format,
+// suitable for a node_exporter textfile collector or a scrape endpoint.
+func (c *Client) Metrics() (string, error) {
+	state, err := c.CurrentState()
+	if err != nil {
+		return "", err
+	}
+
+	var active, remaining float64
+	if state.Pomodoro.IsActive() {
+		active = 1
+		remaining = state.Pomodoro.Remaining().Seconds()
+	}
+
+	completed := 0
+	today := state.History.Date(state.Settings.Day(c.now()))
+	for _, p := range today.Pomodoros {
+		if !p.IsActive() && p.IsCompleted() {
+			completed++
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	writeMetric(buf, "pomodoro_active", "gauge",
+		"Whether a Pomodoro is currently active.", active)
+	writeMetric(buf, "pomodoro_remaining_seconds", "gauge",
+		"Seconds remaining in the current Pomodoro.", remaining)
+	writeMetric(buf, "pomodoro_today_completed", "gauge",
+		"Number of Pomodoros completed today.", float64(completed))
+	writeMetric(buf, "pomodoro_daily_goal", "gauge",
+		"Number of Pomodoros to complete each day.", float64(state.Settings.DailyGoal))
+	writeMetric(buf, "pomodoro_total", "gauge",
+		"Total number of Pomodoros in the history.", float64(state.History.Count()))
+
+	return buf.String(), nil
+}
+
+func writeMetric(buf *bytes.Buffer, name, kind, help string, value float64) {
+	fmt.Fprintf(buf, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(buf, "# TYPE %s %s\n", name, kind)
+	fmt.Fprintf(buf, "%s %g\n", name, value)
+}
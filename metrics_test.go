@@ -0,0 +1,46 @@
+package openpomodoro
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Metrics(t *testing.T) {
+	c, err := NewClient(fixture("settings"), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	require.Nil(t, ioutil.WriteFile(c.SettingsPath(), []byte("daily_goal=8 default_pomodoro_duration=20 keep_cancelled=true"), FilePerm))
+
+	require.Nil(t, c.Start(&Pomodoro{StartTime: fakeTime().Add(-2 * time.Hour)}))
+	timeTravel(-110*time.Minute)(t, c, "")
+	require.Nil(t, c.Cancel())
+	c.Clock.(*FakeClock).Set(fakeTime())
+
+	require.Nil(t, c.Start(&Pomodoro{StartTime: fakeTime().Add(-time.Hour)}))
+	require.Nil(t, c.Start(&Pomodoro{StartTime: fakeTime().Add(-5 * time.Minute)}))
+
+	actual, err := c.Metrics()
+	require.Nil(t, err)
+
+	expected := `# HELP pomodoro_active Whether a Pomodoro is currently active.
+# TYPE pomodoro_active gauge
+pomodoro_active 1
+# HELP pomodoro_remaining_seconds Seconds remaining in the current Pomodoro.
+# TYPE pomodoro_remaining_seconds gauge
+pomodoro_remaining_seconds 900
+# HELP pomodoro_today_completed Number of Pomodoros completed today.
+# TYPE pomodoro_today_completed gauge
+pomodoro_today_completed 1
+# HELP pomodoro_daily_goal Number of Pomodoros to complete each day.
+# TYPE pomodoro_daily_goal gauge
+pomodoro_daily_goal 8
+# HELP pomodoro_total Total number of Pomodoros in the history.
+# TYPE pomodoro_total gauge
+pomodoro_total 3
+`
+	assert.Equal(t, expected, actual)
+}
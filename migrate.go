@@ -0,0 +1,71 @@
+package openpomodoro
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+// HistoryVersion is the version of the `history` file format written by this
+// package. Version 0 is any file written before versions were recorded.
+const HistoryVersion = 1
+
+// ErrUnsupportedVersion is returned by Migrate when the `history` file was
+// written in a newer format than this package supports.
+var ErrUnsupportedVersion = fmt.Errorf("history format is newer than version %d", HistoryVersion)
+
+// Version returns the format version of the `history` file from the `version`
+// file, or 0 if it has not been recorded.
+func (c *Client) Version() (int, error) {
+	b, err := readRaw(c.VersionPath())
+	if err != nil {
+		return 0, err
+	}
+
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 {
+		return 0, nil
+	}
+
+	version, err := strconv.Atoi(string(b))
+	if err != nil {
+		return 0, fmt.Errorf("invalid version %q", b)
+	}
+
+	return version, nil
+}
+
+// Migrate rewrites the `history` file in the current format if it was written
+// in an older one, and records HistoryVersion in the `version` file. Older
+// files are read with the same leniency as History, such as timestamps without
+// a zone and durations with units, so they are upgraded to the canonical form.
+// It does nothing if the file is already current, and returns
+// ErrUnsupportedVersion if it is newer.
+func (c *Client) Migrate() error {
+	version, err := c.Version()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case version == HistoryVersion:
+		return nil
+	case version > HistoryVersion:
+		return ErrUnsupportedVersion
+	}
+
+	h, err := c.History()
+	if err != nil {
+		return err
+	}
+
+	if err := c.ensureDirectory(); err != nil {
+		return err
+	}
+
+	if err := c.writeHistory(h); err != nil {
+		return err
+	}
+
+	return c.writeFile(c.VersionPath(), []byte(strconv.Itoa(HistoryVersion)+"\n"))
+}
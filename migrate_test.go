@@ -0,0 +1,47 @@
+package openpomodoro
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Migrate(t *testing.T) {
+	c, err := NewClient(fixture(""))
+	require.Nil(t, err)
+
+	version, err := c.Version()
+	require.Nil(t, err)
+	assert.Equal(t, 0, version)
+
+	require.Nil(t, c.ensureDirectory())
+	require.Nil(t, ioutil.WriteFile(c.HistoryPath(), []byte(`2016-06-14T10:00:00-04:00 duration=1h tags="deep work",coding
+2016-06-14T09:00:00-04:00 duration=25
+`), FilePerm))
+
+	require.Nil(t, c.Migrate())
+
+	b, err := c.RawHistory()
+	require.Nil(t, err)
+	assert.Equal(t, `2016-06-14T09:00:00-04:00 duration=25
+2016-06-14T10:00:00-04:00 duration=60 tags="deep work,coding"
+`, string(b))
+
+	version, err = c.Version()
+	require.Nil(t, err)
+	assert.Equal(t, HistoryVersion, version)
+
+	require.Nil(t, ioutil.WriteFile(c.HistoryPath(), []byte("2016-06-14T09:00:00-04:00 duration=25m\n"), FilePerm))
+	require.Nil(t, c.Migrate())
+	b, err = c.RawHistory()
+	require.Nil(t, err)
+	assert.Equal(t, "2016-06-14T09:00:00-04:00 duration=25m\n", string(b))
+
+	require.Nil(t, ioutil.WriteFile(c.VersionPath(), []byte("2\n"), FilePerm))
+	assert.Equal(t, ErrUnsupportedVersion, c.Migrate())
+
+	require.Nil(t, ioutil.WriteFile(c.VersionPath(), []byte("two"), FilePerm))
+	assert.NotNil(t, c.Migrate())
+}
@@ -0,0 +1,61 @@
+package openpomodoro
+
+import "time"
+
+// Milestones returned by NextMilestone.
+const (
+	// MilestonePomodoro is finishing the current Pomodoro.
+	MilestonePomodoro = "pomodoro"
+
+	// MilestoneLongBreak is completing enough Pomodoros since the previous
+	// break for a long break, according to the LongBreakInterval setting.
+	// Since zero is replaced by the default, a negative interval turns it
+	// off.
+	MilestoneLongBreak = "long break"
+
+	// MilestoneDailyGoal is reaching the daily goal.
+	MilestoneDailyGoal = "daily goal"
+)
+
+// NextMilestone returns the milestone which needs the fewest more Pomodoros to
+// reach on the day containing now, and how many. A Pomodoro which is running
+// counts as one more. If milestones need the same number, the daily goal is
+// preferred over a long break, and a long break over the current Pomodoro.
+func (c *Client) NextMilestone(now time.Time) (label string, remaining int, err error) {
+	s, err := c.Settings()
+	if err != nil {
+		return "", 0, err
+	}
+
+	current, err := c.Pomodoro()
+	if err != nil {
+		return "", 0, err
+	}
+
+	if current.IsActive() {
+		label, remaining = MilestonePomodoro, 1
+	}
+
+	if s.LongBreakInterval > 0 {
+		inRow, _, err := c.SincePreviousBreak(now)
+		if err != nil {
+			return "", 0, err
+		}
+
+		n := s.LongBreakInterval - inRow%s.LongBreakInterval
+		if label == "" || n <= remaining {
+			label, remaining = MilestoneLongBreak, n
+		}
+	}
+
+	toGoal, _, err := c.RemainingToGoal(now)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if toGoal > 0 && (label == "" || toGoal <= remaining) {
+		label, remaining = MilestoneDailyGoal, toGoal
+	}
+
+	return label, remaining, nil
+}
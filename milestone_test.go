@@ -0,0 +1,63 @@
+package openpomodoro
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_NextMilestone(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	milestone := func() (string, int) {
+		label, remaining, err := c.NextMilestone(fakeTime())
+		require.Nil(t, err)
+		return label, remaining
+	}
+
+	label, remaining := milestone()
+	assert.Equal(t, MilestoneLongBreak, label)
+	assert.Equal(t, 4, remaining)
+
+	require.Nil(t, c.Log(&Pomodoro{}, fakeTime().Add(-55*time.Minute), 25*time.Minute))
+	require.Nil(t, c.Log(&Pomodoro{}, fakeTime().Add(-29*time.Minute), 25*time.Minute))
+
+	label, remaining = milestone()
+	assert.Equal(t, MilestoneLongBreak, label)
+	assert.Equal(t, 2, remaining)
+
+	require.Nil(t, c.Start(&Pomodoro{}))
+
+	label, remaining = milestone()
+	assert.Equal(t, MilestonePomodoro, label)
+	assert.Equal(t, 1, remaining)
+
+	require.Nil(t, ioutil.WriteFile(c.SettingsPath(), []byte("daily_goal=3"), FilePerm))
+
+	label, remaining = milestone()
+	assert.Equal(t, MilestoneDailyGoal, label)
+	assert.Equal(t, 1, remaining)
+
+	require.Nil(t, c.Clear())
+	require.Nil(t, ioutil.WriteFile(c.SettingsPath(), []byte("daily_goal=3 long_break_interval=2"), FilePerm))
+
+	label, remaining = milestone()
+	assert.Equal(t, MilestoneDailyGoal, label)
+	assert.Equal(t, 1, remaining)
+
+	require.Nil(t, ioutil.WriteFile(c.SettingsPath(), []byte("daily_goal=2 long_break_interval=3"), FilePerm))
+
+	label, remaining = milestone()
+	assert.Equal(t, MilestoneLongBreak, label)
+	assert.Equal(t, 1, remaining)
+
+	require.Nil(t, ioutil.WriteFile(c.SettingsPath(), []byte("long_break_interval=-1"), FilePerm))
+
+	label, remaining = milestone()
+	assert.Equal(t, "", label)
+	assert.Equal(t, 0, remaining)
+}
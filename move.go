@@ -0,0 +1,146 @@
+package openpomodoro
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrDirectoryNotEmpty is returned when moving to a directory that already
+// contains files.
+var ErrDirectoryNotEmpty = errors.New("destination directory is not empty")
+
+// MoveTo moves the `current`, `history`, `settings` or `settings.yaml`, `undo`,
+// `journal`, `version`, `schedule`, and `today` files within the Client's directory to a new
+// directory, and returns a copy of the Client for it with the same layout and
+// options. Each file is copied and verified before any originals are removed.
+// It refuses to move into a directory which is not empty.
+func (c *Client) MoveTo(directory string) (*Client, error) {
+	return c.moveTo(directory, false)
+}
+
+// ForceMoveTo is like MoveTo, but overwrites files in a non-empty destination.
+func (c *Client) ForceMoveTo(directory string) (*Client, error) {
+	return c.moveTo(directory, true)
+}
+
+func (c *Client) moveTo(directory string, force bool) (*Client, error) {
+	d, err := filepath.Abs(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	n := *c
+	n.Directory = d
+	n.DryRunWrites = nil
+
+	if n.Directory == c.Directory {
+		return &n, nil
+	}
+
+	// Only files within the directory are moved, keeping their layout. Files
+	// kept elsewhere, such as settings in a separate config directory, stay
+	// where they are.
+	moves := map[string]string{}
+	settingsYAML := n.SettingsFile + ".yaml"
+	for _, file := range []*string{&n.CurrentFile, &n.HistoryFile, &n.SettingsFile, &settingsYAML, &n.undoFile, &n.journalFile, &n.versionFile, &n.scheduleFile, &n.todayFile, &n.breaksFile} {
+		rel, err := filepath.Rel(c.Directory, *file)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+
+		dst := filepath.Join(n.Directory, rel)
+		moves[*file] = dst
+		*file = dst
+	}
+
+	if !force {
+		entries, err := ioutil.ReadDir(n.Directory)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if len(entries) > 0 {
+			return nil, ErrDirectoryNotEmpty
+		}
+	}
+
+	if err := n.ensureDirectory(); err != nil {
+		return nil, err
+	}
+
+	var moved []string
+	for src, dst := range moves {
+		ok, err := c.copyFile(src, dst)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			moved = append(moved, src)
+		}
+	}
+
+	for _, src := range moved {
+		if err := c.removeFile(src); err != nil {
+			return nil, err
+		}
+	}
+
+	return &n, nil
+}
+
+// copyFile copies src to dst by writing a temporary file and renaming it into
+// place, then verifies the contents. It returns false if src does not exist.
+func (c *Client) copyFile(src, dst string) (bool, error) {
+	b, err := ioutil.ReadFile(src)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if c.ReadOnly {
+		return false, ErrReadOnly
+	}
+
+	if c.DryRun {
+		return true, c.writeFile(dst, b)
+	}
+
+	tmp := dst + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, c.filePerm()); err != nil {
+		return false, err
+	}
+
+	if err := os.Rename(tmp, dst); err != nil {
+		return false, err
+	}
+
+	written, err := ioutil.ReadFile(dst)
+	if err != nil {
+		return false, err
+	}
+
+	if !bytes.Equal(b, written) {
+		return false, fmt.Errorf("verification of %s failed", dst)
+	}
+
+	return true, nil
+}
+
+func (c *Client) removeFile(file string) error {
+	if c.ReadOnly {
+		return ErrReadOnly
+	}
+
+	if c.DryRun {
+		c.DryRunWrites = append(c.DryRunWrites, Write{File: file, Remove: true})
+		return nil
+	}
+
+	return os.Remove(file)
+}
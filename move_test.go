@@ -0,0 +1,111 @@
+package openpomodoro
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_MoveTo(t *testing.T) {
+	c, err := NewClient(fixture("settings"))
+	require.Nil(t, err)
+
+	require.Nil(t, c.Start(&Pomodoro{Description: "moving"}))
+
+	n, err := c.MoveTo(filepath.Join(fixture(""), "moved"))
+	require.Nil(t, err)
+
+	p, err := n.Pomodoro()
+	require.Nil(t, err)
+	assert.Equal(t, "moving", p.Description)
+
+	h, err := n.History()
+	require.Nil(t, err)
+	assert.Equal(t, 1, h.Count())
+
+	s, err := n.Settings()
+	require.Nil(t, err)
+	assert.Equal(t, 8, s.DailyGoal)
+
+	for _, f := range []string{c.CurrentPath(), c.HistoryPath(), c.SettingsPath()} {
+		_, err := os.Stat(f)
+		assert.True(t, os.IsNotExist(err), f)
+	}
+}
+
+func Test_MoveTo_notEmpty(t *testing.T) {
+	c, err := NewClient(fixture("settings"))
+	require.Nil(t, err)
+
+	dst := fixture("")
+	require.Nil(t, os.MkdirAll(dst, 0755))
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dst, "settings"), []byte("daily_goal=2"), FilePerm))
+
+	_, err = c.MoveTo(dst)
+	assert.Equal(t, ErrDirectoryNotEmpty, err)
+
+	n, err := c.ForceMoveTo(dst)
+	require.Nil(t, err)
+
+	s, err := n.Settings()
+	require.Nil(t, err)
+	assert.Equal(t, 8, s.DailyGoal)
+}
+
+func Test_MoveTo_dryRun(t *testing.T) {
+	c, err := NewClient(fixture("settings"))
+	require.Nil(t, err)
+	c.DryRun = true
+
+	dst := filepath.Join(fixture(""), "moved")
+	n, err := c.MoveTo(dst)
+	require.Nil(t, err)
+	assert.True(t, n.DryRun)
+
+	settings, err := ioutil.ReadFile(c.SettingsPath())
+	require.Nil(t, err)
+
+	assert.Equal(t, []Write{
+		{File: n.SettingsPath(), Data: settings},
+		{File: c.SettingsPath(), Remove: true},
+	}, c.DryRunWrites)
+
+	_, err = os.Stat(dst)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func Test_MoveTo_splitLayout(t *testing.T) {
+	root := fixture("")
+	config := filepath.Join(root, "cfg", "settings")
+
+	c, err := NewClient(filepath.Join(root, "state"),
+		WithSettingsFile(config),
+		WithHistoryFile("log/history"),
+	)
+	require.Nil(t, err)
+	c.Notifier = &recordingNotifier{}
+
+	require.Nil(t, c.ensureDirectory())
+	require.Nil(t, ioutil.WriteFile(config, []byte("daily_goal=8"), FilePerm))
+	require.Nil(t, c.Start(&Pomodoro{}))
+
+	dst := filepath.Join(root, "moved")
+	n, err := c.MoveTo(dst)
+	require.Nil(t, err)
+
+	assert.Equal(t, filepath.Join(dst, "current"), n.CurrentPath())
+	assert.Equal(t, filepath.Join(dst, "log", "history"), n.HistoryPath())
+	assert.Equal(t, config, n.SettingsPath())
+	assert.Equal(t, c.Notifier, n.Notifier)
+
+	_, err = os.Stat(config)
+	assert.Nil(t, err)
+
+	h, err := n.History()
+	require.Nil(t, err)
+	assert.Equal(t, 1, h.Count())
+}
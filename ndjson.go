@@ -0,0 +1,53 @@
+package openpomodoro
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// MarshalNDJSON returns each Pomodoro in the History marshaled as JSON, one
+// object per line.
+func (h History) MarshalNDJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := h.WriteNDJSON(buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// WriteNDJSON writes each Pomodoro in the History to w as JSON, one object per
+// line.
+func (h History) WriteNDJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for _, p := range h.Pomodoros {
+		if err := enc.Encode(p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReadNDJSON reads a History from r, which contains one JSON object per
+// Pomodoro as written by WriteNDJSON.
+func ReadNDJSON(r io.Reader) (*History, error) {
+	h := &History{Pomodoros: []*Pomodoro{}}
+
+	dec := json.NewDecoder(r)
+	for {
+		p := &Pomodoro{}
+		err := dec.Decode(p)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		h.Pomodoros = append(h.Pomodoros, p)
+	}
+
+	return h, nil
+}
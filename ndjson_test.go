@@ -0,0 +1,42 @@
+package openpomodoro
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory_MarshalNDJSON(t *testing.T) {
+	h := &History{Pomodoros: []*Pomodoro{
+		{
+			StartTime:   time.Date(2016, 06, 14, 12, 0, 0, 0, time.UTC),
+			Duration:    25 * time.Minute,
+			Tags:        []string{"a", "b"},
+			Description: "A description",
+		},
+		{
+			StartTime: time.Date(2016, 06, 14, 13, 0, 0, 0, time.UTC),
+			Duration:  20 * time.Minute,
+		},
+	}}
+
+	b, err := h.MarshalNDJSON()
+	require.Nil(t, err)
+	assert.Equal(t,
+		`{"start_time":"2016-06-14T12:00:00Z","description":"A description","duration":25,"end_time":"2016-06-14T12:25:00Z","tags":["a","b"]}
+{"start_time":"2016-06-14T13:00:00Z","description":"","duration":20,"end_time":"2016-06-14T13:20:00Z","tags":null}
+`,
+		string(b))
+
+	actual, err := ReadNDJSON(bytes.NewReader(b))
+	require.Nil(t, err)
+	assert.Equal(t, h, actual)
+}
+
+func Test_ReadNDJSON_invalid(t *testing.T) {
+	_, err := ReadNDJSON(bytes.NewBufferString("{\"start_time\":\n"))
+	assert.NotNil(t, err)
+}
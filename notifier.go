@@ -0,0 +1,58 @@
+package openpomodoro
+
+import "log"
+
+// Lifecycle events passed to a Notifier.
+const (
+	EventStart  = "start"
+	EventFinish = "finish"
+	EventCancel = "cancel"
+	EventDone   = "done"
+	EventBreak  = "break"
+)
+
+// Notifier is notified by a Client when a Pomodoro changes state, such as
+// when it is started, finished, cancelled, or done. EventBreak is sent with
+// the Pomodoro which just ended when a break begins, either because it was
+// finished early or because it became done while waiting.
+//
+// Notifications are sent only after the state change has been written, and
+// an error from Notify never fails the operation; see Client.OnNotifyError.
+type Notifier interface {
+	Notify(event string, p *Pomodoro) error
+}
+
+// LogNotifier is a Notifier which logs each event.
+type LogNotifier struct {
+	// Logger is the logger to write to. If nil, the standard logger is used.
+	Logger *log.Logger
+}
+
+// Notify implements Notifier.
+func (n *LogNotifier) Notify(event string, p *Pomodoro) error {
+	if n.Logger == nil {
+		log.Printf("%s %s", event, p)
+		return nil
+	}
+
+	n.Logger.Printf("%s %s", event, p)
+	return nil
+}
+
+func (c *Client) notify(event string, p *Pomodoro) {
+	if c.Notifier == nil || c.DryRun {
+		return
+	}
+
+	err := c.Notifier.Notify(event, p)
+	if err == nil {
+		return
+	}
+
+	if c.OnNotifyError != nil {
+		c.OnNotifyError(event, p, err)
+		return
+	}
+
+	debug("notifying %s: %s", event, err)
+}
@@ -0,0 +1,73 @@
+package openpomodoro
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingNotifier struct {
+	events []string
+}
+
+func (n *recordingNotifier) Notify(event string, p *Pomodoro) error {
+	n.events = append(n.events, event)
+	return nil
+}
+
+func Test_Notifier(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	n := &recordingNotifier{}
+	c.Notifier = n
+
+	require.Nil(t, c.Cancel())
+	require.Nil(t, c.Start(&Pomodoro{StartTime: fakeTime().Add(-time.Minute)}))
+	require.Nil(t, c.Start(&Pomodoro{}))
+	require.Nil(t, c.Finish())
+
+	assert.Equal(t, []string{EventStart, EventCancel, EventStart, EventFinish, EventBreak}, n.events)
+}
+
+type failingNotifier struct{}
+
+func (n *failingNotifier) Notify(event string, p *Pomodoro) error {
+	return errors.New("unreachable")
+}
+
+func Test_Notifier_errors(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	var failed []string
+	c.Notifier = &failingNotifier{}
+	c.OnNotifyError = func(event string, p *Pomodoro, err error) {
+		failed = append(failed, event+": "+err.Error())
+	}
+
+	require.Nil(t, c.Start(&Pomodoro{StartTime: fakeTime().Add(-time.Minute)}))
+	require.Nil(t, c.Start(&Pomodoro{}))
+
+	assertActive(true)(t, c, "")
+	assertHistoryLength(1)(t, c, "")
+	assert.Equal(t, []string{"start: unreachable", "cancel: unreachable", "start: unreachable"}, failed)
+}
+
+func Test_LogNotifier(t *testing.T) {
+	buf := &bytes.Buffer{}
+	n := &LogNotifier{Logger: log.New(buf, "", 0)}
+
+	p := &Pomodoro{
+		StartTime: time.Date(2016, 06, 14, 12, 0, 0, 0, time.UTC),
+		Duration:  25 * time.Minute,
+	}
+
+	require.Nil(t, n.Notify(EventStart, p))
+	assert.Equal(t, "start 2016-06-14T12:00:00Z duration=25\n", buf.String())
+}
@@ -0,0 +1,135 @@
+package openpomodoro
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Option changes the configuration of a Client created by NewClient.
+type Option func(*Client)
+
+// WithCurrentFile sets the location of the `current` file. A relative path is
+// relative to the Client's directory.
+func WithCurrentFile(file string) Option {
+	return func(c *Client) {
+		c.CurrentFile = resolvePath(c.Directory, file)
+	}
+}
+
+// WithHistoryFile sets the location of the `history` file. A relative path is
+// relative to the Client's directory.
+func WithHistoryFile(file string) Option {
+	return func(c *Client) {
+		c.HistoryFile = resolvePath(c.Directory, file)
+	}
+}
+
+// WithSettingsFile sets the location of the `settings` file. A relative path
+// is relative to the Client's directory.
+func WithSettingsFile(file string) Option {
+	return func(c *Client) {
+		c.SettingsFile = resolvePath(c.Directory, file)
+	}
+}
+
+// WithUndoFile sets the location of the `undo` file. A relative path is
+// relative to the Client's directory.
+func WithUndoFile(file string) Option {
+	return func(c *Client) {
+		c.undoFile = resolvePath(c.Directory, file)
+	}
+}
+
+// WithJournalFile sets the location of the `journal` file. A relative path is
+// relative to the Client's directory.
+func WithJournalFile(file string) Option {
+	return func(c *Client) {
+		c.journalFile = resolvePath(c.Directory, file)
+	}
+}
+
+// WithVersionFile sets the location of the `version` file. A relative path is
+// relative to the Client's directory.
+func WithVersionFile(file string) Option {
+	return func(c *Client) {
+		c.versionFile = resolvePath(c.Directory, file)
+	}
+}
+
+// WithScheduleFile sets the location of the `schedule` file. A relative path
+// is relative to the Client's directory.
+func WithScheduleFile(file string) Option {
+	return func(c *Client) {
+		c.scheduleFile = resolvePath(c.Directory, file)
+	}
+}
+
+// WithTodayFile sets the location of the `today` file. A relative path is
+// relative to the Client's directory.
+func WithTodayFile(file string) Option {
+	return func(c *Client) {
+		c.todayFile = resolvePath(c.Directory, file)
+	}
+}
+
+// WithBreaksFile sets the location of the `breaks` file. A relative path is
+// relative to the Client's directory.
+func WithBreaksFile(file string) Option {
+	return func(c *Client) {
+		c.breaksFile = resolvePath(c.Directory, file)
+	}
+}
+
+// WithTodayCache enables the TodayCache.
+func WithTodayCache() Option {
+	return func(c *Client) {
+		c.TodayCache = true
+	}
+}
+
+// WithoutHistory sets DisableHistory, so that the Client only keeps the
+// `current` file.
+func WithoutHistory() Option {
+	return func(c *Client) {
+		c.DisableHistory = true
+	}
+}
+
+// WithHistoryOrder sets the order of Pomodoros in the `history` file.
+func WithHistoryOrder(order HistoryOrder) Option {
+	return func(c *Client) {
+		c.HistoryOrder = order
+	}
+}
+
+// WithReadOnly makes the Client ReadOnly.
+func WithReadOnly() Option {
+	return func(c *Client) {
+		c.ReadOnly = true
+	}
+}
+
+// WithPermissions sets the permissions of created directories and files, such
+// as 0700 and 0600 to keep them private.
+func WithPermissions(dir, file os.FileMode) Option {
+	return func(c *Client) {
+		c.DirPerm = dir
+		c.FilePerm = file
+	}
+}
+
+// WithClock sets the Clock the Client gets the current time from, such as a
+// FakeClock in tests.
+func WithClock(clock Clock) Option {
+	return func(c *Client) {
+		c.Clock = clock
+	}
+}
+
+func resolvePath(directory string, file string) string {
+	if filepath.IsAbs(file) {
+		return file
+	}
+
+	return filepath.Join(directory, file)
+}
@@ -0,0 +1,75 @@
+package openpomodoro
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewClient_options(t *testing.T) {
+	c, err := NewClient("/tmp/pomodoro",
+		WithCurrentFile("now"),
+		WithSettingsFile("/etc/pomodoro/settings"),
+		WithUndoFile("state/undo"),
+		WithJournalFile("/var/pomodoro/journal"),
+		WithVersionFile("state/version"),
+		WithScheduleFile("plans"),
+		WithTodayFile("/var/cache/pomodoro/today"),
+		WithBreaksFile("state/breaks"),
+	)
+	require.Nil(t, err)
+
+	assert.Equal(t, "/tmp/pomodoro/now", c.CurrentPath())
+	assert.Equal(t, "/tmp/pomodoro/history", c.HistoryPath())
+	assert.Equal(t, "/etc/pomodoro/settings", c.SettingsPath())
+	assert.Equal(t, "/tmp/pomodoro/state/undo", c.UndoPath())
+	assert.Equal(t, "/var/pomodoro/journal", c.JournalPath())
+	assert.Equal(t, "/tmp/pomodoro/state/version", c.VersionPath())
+	assert.Equal(t, "/tmp/pomodoro/plans", c.SchedulePath())
+	assert.Equal(t, "/var/cache/pomodoro/today", c.TodayPath())
+	assert.Equal(t, "/tmp/pomodoro/state/breaks", c.BreaksPath())
+}
+
+func Test_NewClient_splitLayout(t *testing.T) {
+	root := fixture("")
+	config := filepath.Join(root, "config", "pomodoro")
+	state := filepath.Join(root, "state", "pomodoro")
+
+	c, err := NewClient(state, WithSettingsFile(filepath.Join(config, "settings")))
+	require.Nil(t, err)
+
+	require.Nil(t, c.Start(&Pomodoro{}))
+
+	_, err = os.Stat(filepath.Join(state, "current"))
+	assert.Nil(t, err)
+
+	_, err = os.Stat(config)
+	assert.Nil(t, err)
+}
+
+func Test_NewClient_withPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not supported on Windows")
+	}
+
+	dir := filepath.Join(fixture(""), "pomodoro")
+	c, err := NewClient(dir, WithPermissions(0700, 0600))
+	require.Nil(t, err)
+
+	require.Nil(t, c.Start(&Pomodoro{}))
+	require.Nil(t, c.Finish())
+
+	info, err := os.Stat(dir)
+	require.Nil(t, err)
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+
+	for _, file := range []string{c.CurrentPath(), c.HistoryPath()} {
+		info, err := os.Stat(file)
+		require.Nil(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), file)
+	}
+}
@@ -0,0 +1,54 @@
+package openpomodoro
+
+// Phase is what a State is doing at a point in time.
+type Phase string
+
+const (
+	// PhaseInactive is when no Pomodoro is running and no break is due.
+	PhaseInactive Phase = "inactive"
+
+	// PhaseActive is when a Pomodoro is running.
+	PhaseActive Phase = "active"
+
+	// PhaseDone is when the current Pomodoro's time is up, but it has not
+	// been finished or cleared.
+	PhaseDone Phase = "done"
+
+	// PhaseBreak is when there is no current Pomodoro, and the latest one in
+	// the history was completed less than the DefaultBreakDuration ago.
+	PhaseBreak Phase = "break"
+)
+
+// Phase returns the phase of the State now, by the Pomodoro's Clock. The time
+// is read once, so the result is consistent, unlike calling IsActive, IsDone,
+// and IsInactive in turn.
+func (s *State) Phase() Phase {
+	var clock Clock
+	if s.Pomodoro != nil {
+		clock = s.Pomodoro.Clock
+	}
+	now := clockNow(clock)
+
+	if p := s.Pomodoro; p != nil && !p.IsInactive() && !now.Before(p.StartTime) {
+		if now.After(p.EndTime()) {
+			return PhaseDone
+		}
+		return PhaseActive
+	}
+
+	if s.History == nil || s.Settings == nil {
+		return PhaseInactive
+	}
+
+	latest := s.History.Latest()
+	if latest == nil || !latest.IsCompleted() {
+		return PhaseInactive
+	}
+
+	end := latest.EndTime()
+	if !now.Before(end) && now.Before(end.Add(s.Settings.DefaultBreakDuration)) {
+		return PhaseBreak
+	}
+
+	return PhaseInactive
+}
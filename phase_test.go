@@ -0,0 +1,43 @@
+package openpomodoro
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestState_Phase(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	phase := func() Phase {
+		state, err := c.CurrentState()
+		require.Nil(t, err)
+		return state.Phase()
+	}
+
+	assert.Equal(t, PhaseInactive, phase())
+
+	require.Nil(t, c.Start(&Pomodoro{}))
+	assert.Equal(t, PhaseActive, phase())
+
+	timeTravel(25*time.Minute)(t, c, "")
+	assert.Equal(t, PhaseActive, phase())
+
+	timeTravel(time.Minute)(t, c, "")
+	assert.Equal(t, PhaseDone, phase())
+
+	require.Nil(t, c.Finish())
+	assert.Equal(t, PhaseBreak, phase())
+
+	timeTravel(5*time.Minute)(t, c, "")
+	assert.Equal(t, PhaseInactive, phase())
+
+	require.Nil(t, c.Start(&Pomodoro{}))
+	require.Nil(t, c.Cancel())
+	assert.Equal(t, PhaseInactive, phase())
+
+	assert.Equal(t, PhaseInactive, (&State{}).Phase())
+}
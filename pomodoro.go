@@ -3,7 +3,11 @@ package openpomodoro
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"math"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/justincampbell/go-logfmt"
@@ -12,12 +16,25 @@ import (
 const (
 	// TimeFormat is the format we generate and expect to parse timestamps in.
 	TimeFormat = time.RFC3339
+
+	// DateFormat is the format of dates used as keys when grouping by day.
+	DateFormat = "2006-01-02"
+
+	// MaxDuration is the longest duration a Pomodoro may be given.
+	MaxDuration = 24 * time.Hour
 )
 
+// ErrBelowMinDuration is returned when a Pomodoro is shorter than the
+// MinDuration setting.
+var ErrBelowMinDuration = errors.New("duration is below the minimum")
+
+// ErrDurationTooLong is returned when a Pomodoro would be given a duration
+// longer than MaxDuration.
+var ErrDurationTooLong = errors.New("duration is above the maximum")
+
 var (
 	charNewline = []byte("\n")
 	charSpace   = []byte(" ")
-	timeFunc    = time.Now
 )
 
 // Pomodoro holds a single Pomodoro and related information.
@@ -28,14 +45,64 @@ type Pomodoro struct {
 	// Description is a description of the Pomodoro.
 	Description string `logfmt:"description" json:"description"`
 
-	// Duration is the length of the Pomodoro.
+	// Notes is a longer, free-form annotation of the Pomodoro. Unlike the
+	// description, it is not meant to be displayed inline.
+	Notes string `logfmt:"notes" json:"notes,omitempty"`
+
+	// Duration is the length of the Pomodoro, which is planned while it is
+	// running and actual once it is finished or cancelled. A zero duration is
+	// omitted from the text, so that it is read back as not specified.
 	Duration time.Duration `logfmt:"duration,m" json:"-"`
 	// JSONDuration is a placeholder for MarshalJSON to convert and store the
 	// duration in minutes.
 	JSONDuration int `json:"duration"`
 
+	// PlannedDuration is the duration the Pomodoro was started with. It is
+	// only recorded when the actual duration turned out different, so use
+	// Planned to read it.
+	PlannedDuration time.Duration `logfmt:"planned,m" json:"-"`
+	// JSONPlannedDuration is a placeholder for MarshalJSON to convert and
+	// store the planned duration in minutes.
+	JSONPlannedDuration int `json:"planned_duration,omitempty"`
+	// JSONEndTime is a placeholder for MarshalJSON to store the computed end
+	// time. It is ignored when reading.
+	JSONEndTime time.Time `json:"end_time"`
+
 	// Tags are the list of tags for this Pomodoro.
 	Tags []string `logfmt:"tags" json:"tags"`
+
+	// Color is an optional color for frontends to display the Pomodoro with,
+	// such as "#ff6347" or "red". It is not interpreted.
+	Color string `logfmt:"color" json:"color,omitempty"`
+
+	// Cancelled is whether the Pomodoro was cancelled before it was done.
+	Cancelled bool `json:"cancelled,omitempty"`
+
+	// Reason is an optional explanation of why the Pomodoro was cancelled.
+	Reason string `logfmt:"reason" json:"reason,omitempty"`
+
+	// Scheduled is whether the Pomodoro is planned to start at its StartTime,
+	// rather than having been started. See IsScheduled.
+	Scheduled bool `json:"scheduled,omitempty"`
+
+	// ClockSkew is whether the clock went backwards while the Pomodoro was
+	// running, so that its duration could not be known and is zero.
+	ClockSkew bool `json:"clock_skew,omitempty"`
+
+	// TagSeparator separates the tags in text, and is a comma if empty. It is
+	// not stored, and is set from settings by ApplySettings and when read by a
+	// Client.
+	TagSeparator string `json:"-"`
+
+	// Rounding is the policy used by DurationMinutes and RemainingMinutes. It
+	// is not stored, and is set from settings by ApplySettings and when read
+	// by a Client.
+	Rounding Rounding `json:"-"`
+
+	// Clock is where the Pomodoro gets the current time, which is the system
+	// time if nil. It is not stored, and is set from the Client when read or
+	// started by one.
+	Clock Clock `json:"-"`
 }
 
 // NewPomodoro returns a Pomodoro with defaults set.
@@ -50,32 +117,98 @@ func EmptyPomodoro() *Pomodoro {
 	return &Pomodoro{}
 }
 
-// String return a string representation of the Pomodoro.
+// String return a string representation of the Pomodoro. Notes are omitted to
+// keep it compact.
 func (p Pomodoro) String() string {
+	p.Notes = ""
 	b, _ := p.MarshalText()
 	return string(b)
 }
 
+// Copy returns a deep copy of the Pomodoro.
+func (p *Pomodoro) Copy() *Pomodoro {
+	c := *p
+	c.Tags = copyStrings(p.Tags)
+	return &c
+}
+
 // Matches returns whether or not another Pomodoro has the same StartTime.
 func (p Pomodoro) Matches(o *Pomodoro) bool {
 	delta := p.StartTime.Sub(o.StartTime)
 	return delta >= -time.Second && delta <= time.Second
 }
 
+// Equal returns whether or not another Pomodoro has the same content, which is
+// everything stored in the text, including notes. Start times are compared as
+// instants, and nil tags are the same as no tags.
+func (p *Pomodoro) Equal(o *Pomodoro) bool {
+	return p.StartTime.Equal(o.StartTime) &&
+		p.Description == o.Description &&
+		p.Notes == o.Notes &&
+		p.Duration == o.Duration &&
+		p.PlannedDuration == o.PlannedDuration &&
+		equalStrings(p.Tags, o.Tags) &&
+		p.Color == o.Color &&
+		p.Cancelled == o.Cancelled &&
+		p.Reason == o.Reason &&
+		p.ClockSkew == o.ClockSkew &&
+		p.Scheduled == o.Scheduled
+}
+
+// TagsContain returns whether or not the Pomodoro has the tag. Tags are
+// compared exactly, including case, as they are by History.TagCounts.
+func (p *Pomodoro) TagsContain(tag string) bool {
+	for _, t := range p.Tags {
+		if t == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Overlaps returns whether or not the time between the start and end of
+// another Pomodoro intersects with this one. Each Pomodoro covers the half-open
+// interval [StartTime, EndTime()), so one which ends exactly when the other
+// starts does not overlap it.
+func (p *Pomodoro) Overlaps(o *Pomodoro) bool {
+	return p.StartTime.Before(o.EndTime()) && o.StartTime.Before(p.EndTime())
+}
+
 // MarshalJSON implements json.Marshaler.
 func (p Pomodoro) MarshalJSON() ([]byte, error) {
 	// This is required so that json.Marshal ignores that we also implement
 	// encoding.TextMarshaler via MarshalText.
 	type alias Pomodoro
 	p.JSONDuration = p.DurationMinutes()
+	p.JSONPlannedDuration = p.Rounding.Minutes(p.PlannedDuration)
+	p.JSONEndTime = p.EndTime()
 	return json.Marshal((alias)(p))
 }
 
+// UnmarshalJSON implements json.Unmarshaler. The end time is derived, so it is
+// ignored.
+func (p *Pomodoro) UnmarshalJSON(b []byte) error {
+	type alias Pomodoro
+	a := (*alias)(p)
+	if err := json.Unmarshal(b, a); err != nil {
+		return err
+	}
+
+	p.Duration = time.Duration(p.JSONDuration) * time.Minute
+	p.PlannedDuration = time.Duration(p.JSONPlannedDuration) * time.Minute
+	p.JSONDuration = 0
+	p.JSONPlannedDuration = 0
+	p.JSONEndTime = time.Time{}
+
+	return nil
+}
+
 // MarshalText marshals the Pomodoro's start time and attributes into a text
 // string.
 func (p Pomodoro) MarshalText() ([]byte, error) {
 	timestamp := []byte(p.StartTime.Format(TimeFormat))
-	attributes, err := logfmt.Encode(p)
+	attributes, err := p.AttributesText()
 	if err != nil {
 		return nil, err
 	}
@@ -83,8 +216,34 @@ func (p Pomodoro) MarshalText() ([]byte, error) {
 	return bytes.Join([][]byte{timestamp, attributes}, charSpace), nil
 }
 
+// AttributesText marshals only the Pomodoro's attributes into a text string,
+// without the start time. This is useful for previewing a Pomodoro which has
+// not started yet.
+func (p Pomodoro) AttributesText() ([]byte, error) {
+	if p.TagSeparator != "" && len(p.Tags) > 0 {
+		p.Tags = []string{strings.Join(p.Tags, p.TagSeparator)}
+	}
+
+	attributes, err := logfmt.Encode(p)
+	if err != nil {
+		return nil, err
+	}
+
+	flags, err := p.marshalFlags()
+	if err != nil {
+		return nil, err
+	}
+	if len(flags) > 0 {
+		attributes = bytes.Join([][]byte{attributes, flags}, charSpace)
+		attributes = bytes.TrimSpace(attributes)
+	}
+
+	return attributes, nil
+}
+
 // UnmarshalText updates a Pomodoro's timestamp and attributes from a byte
-// string.
+// string. Tags are split by the Pomodoro's TagSeparator, and may also be quoted
+// individually, such as tags="deep work",coding.
 func (p *Pomodoro) UnmarshalText(b []byte) error {
 	b = bytes.TrimSpace(b)
 	parts := bytes.SplitN(b, charSpace, 2)
@@ -111,36 +270,246 @@ func (p *Pomodoro) UnmarshalText(b []byte) error {
 		return nil
 	}
 
-	startTime, err := time.Parse(TimeFormat, string(timestamp))
+	startTime, attributes, err := parseTimestamp(timestamp, attributes)
 	if err != nil {
 		return err
 	}
 
 	p.StartTime = startTime
 
+	attributes, err = normalizeTags(attributes)
+	if err != nil {
+		return err
+	}
+
+	attributes, err = normalizeMinutes(attributes, "duration", "planned")
+	if err != nil {
+		return err
+	}
+
 	err = logfmt.Unmarshal(attributes, p)
 	if err != nil {
 		return err
 	}
 
+	p.Tags = splitTags(p.Tags, p.TagSeparator)
+
+	err = p.unmarshalFlags(attributes)
+	if err != nil {
+		return err
+	}
+
+	// A zero duration is omitted from the text, so it would otherwise be read
+	// as the default duration. A planned duration is only recorded once the
+	// actual duration is known, so it also means the duration was zero.
+	if (p.ClockSkew || hasKey(attributes, "planned")) && !hasKey(attributes, "duration") {
+		p.Duration = 0
+	}
+
 	return nil
 }
 
+// fallbackTimeFormats are the layouts tried in order when a timestamp is not
+// in TimeFormat, so that hand-edited files are forgiving. Layouts without a
+// zone are parsed in local time. Layouts with a space consume the first
+// attribute as the time of day.
+var fallbackTimeFormats = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// parseTimestamp parses the timestamp in TimeFormat, or else in one of the
+// fallbackTimeFormats, and returns the attributes which remain.
+func parseTimestamp(timestamp []byte, attributes []byte) (time.Time, []byte, error) {
+	t, err := time.Parse(TimeFormat, string(timestamp))
+	if err == nil {
+		return t, attributes, nil
+	}
+
+	parts := bytes.SplitN(attributes, charSpace, 2)
+	clock, rest := parts[0], []byte(nil)
+	if len(parts) == 2 {
+		rest = parts[1]
+	}
+
+	for _, layout := range fallbackTimeFormats {
+		value, remaining := string(timestamp), attributes
+		if strings.Contains(layout, " ") {
+			value, remaining = value+" "+string(clock), rest
+		}
+
+		t, fallbackErr := time.ParseInLocation(layout, value, time.Local)
+		if fallbackErr == nil {
+			debug("parsed timestamp %q with fallback layout %q", value, layout)
+			return t, remaining, nil
+		}
+	}
+
+	return time.Time{}, nil, err
+}
+
+// flag is a boolean attribute of a Pomodoro. Flags are handled separately
+// from the logfmt tags so that false values are omitted, and a bare key is
+// considered true.
+type flag struct {
+	key   string
+	value *bool
+}
+
+func (p *Pomodoro) flags() []flag {
+	return []flag{
+		{"cancelled", &p.Cancelled},
+		{"clock_skew", &p.ClockSkew},
+		{"scheduled", &p.Scheduled},
+	}
+}
+
+func (p Pomodoro) marshalFlags() ([]byte, error) {
+	var keyvals []interface{}
+	for _, flag := range p.flags() {
+		if *flag.value {
+			keyvals = append(keyvals, flag.key, true)
+		}
+	}
+
+	return logfmt.MarshalKeyvals(keyvals...)
+}
+
+// hasKey returns whether or not a logfmt record has the key.
+func hasKey(b []byte, key string) bool {
+	d := logfmt.NewDecoder(bytes.NewReader(b))
+	if !d.ScanRecord() {
+		return false
+	}
+
+	for d.ScanKeyval() {
+		if string(d.Key()) == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *Pomodoro) unmarshalFlags(b []byte) error {
+	d := logfmt.NewDecoder(bytes.NewReader(b))
+	if !d.ScanRecord() {
+		return d.Err()
+	}
+
+	for d.ScanKeyval() {
+		for _, flag := range p.flags() {
+			if string(d.Key()) != flag.key {
+				continue
+			}
+
+			if d.Value() == nil {
+				*flag.value = true
+				continue
+			}
+
+			v, err := strconv.ParseBool(string(d.Value()))
+			if err != nil {
+				return fmt.Errorf("Error while parsing %s: %s", flag.key, err)
+			}
+			*flag.value = v
+		}
+	}
+
+	return d.Err()
+}
+
 // ApplySettings sets the Pomodoro's defaults from settings if they are
-// considered to be missing.
+// considered to be missing. Tags come from the first tag rule matching the
+// start time, or else the default tags.
 func (p *Pomodoro) ApplySettings(s *Settings) {
 	if p.Duration == 0 {
 		p.Duration = s.DefaultPomodoroDuration
 	}
 
 	if len(p.Tags) == 0 {
-		p.Tags = s.DefaultTags
+		p.Tags = s.TagRules.Tags(p.StartTime)
+	}
+
+	if len(p.Tags) == 0 {
+		p.Tags = copyStrings(s.DefaultTags)
+	}
+
+	if p.Rounding == "" {
+		p.Rounding = s.Rounding
+	}
+
+	if p.TagSeparator == "" {
+		p.TagSeparator = s.TagSeparator
+	}
+}
+
+// Validate returns ErrInvalidDuration if the Pomodoro's duration is not
+// positive, such as when the default duration is misconfigured, so that it
+// would be done as soon as it started. It returns ErrDurationTooLong if the
+// duration is longer than MaxDuration, and ErrBelowMinDuration if it is
+// shorter than the MinDuration setting.
+func (p *Pomodoro) Validate(s *Settings) error {
+	if p.Duration <= 0 {
+		return ErrInvalidDuration
+	}
+
+	if p.Duration > MaxDuration {
+		return ErrDurationTooLong
+	}
+
+	if p.Duration < s.MinDuration {
+		return ErrBelowMinDuration
+	}
+
+	return nil
+}
+
+// SetDuration sets the duration, leaving the Pomodoro unchanged and returning
+// ErrInvalidDuration if it is negative, or ErrDurationTooLong if it is longer
+// than MaxDuration. A zero duration is not specified. The end time follows
+// from the duration, so this also moves the end of a running Pomodoro.
+func (p *Pomodoro) SetDuration(d time.Duration) error {
+	if d < 0 {
+		return ErrInvalidDuration
+	}
+
+	if d > MaxDuration {
+		return ErrDurationTooLong
+	}
+
+	p.Duration = d
+	return nil
+}
+
+// Planned returns the duration the Pomodoro was started with, which is the
+// PlannedDuration if it was recorded, and otherwise the Duration.
+func (p *Pomodoro) Planned() time.Duration {
+	if p.PlannedDuration != 0 {
+		return p.PlannedDuration
+	}
+
+	return p.Duration
+}
+
+// setActualDuration sets the duration to how long the Pomodoro actually took,
+// and records the planned duration if it is different. Durations are compared
+// in whole minutes, since that is how they are stored.
+func (p *Pomodoro) setActualDuration(d time.Duration) {
+	if p.PlannedDuration == 0 && round(d.Minutes()) != round(p.Duration.Minutes()) {
+		p.PlannedDuration = p.Duration
 	}
+
+	p.Duration = d
 }
 
-// DurationMinutes returns the Pomodoro's duration in minutes.
+// DurationMinutes returns the Pomodoro's duration in minutes, rounded
+// according to the Pomodoro's Rounding policy.
 func (p *Pomodoro) DurationMinutes() int {
-	return round(p.Duration.Minutes())
+	return p.Rounding.Minutes(p.Duration)
 }
 
 // EndTime returns the time the Pomodoro would end.
@@ -148,9 +517,36 @@ func (p *Pomodoro) EndTime() time.Time {
 	return p.StartTime.Add(p.Duration)
 }
 
-// IsActive returns whether or not a Pomodoro is active.
+// IsOnDay returns whether or not the Pomodoro started on the same calendar day
+// as the given date, in the date's location.
+func (p *Pomodoro) IsOnDay(date time.Time) bool {
+	y, m, d := date.Date()
+	py, pm, pd := p.StartTime.In(date.Location()).Date()
+	return y == py && m == pm && d == pd
+}
+
+// IsToday returns whether or not the Pomodoro started on the same calendar day
+// as now.
+func (p *Pomodoro) IsToday(now time.Time) bool {
+	return p.IsOnDay(now)
+}
+
+// IsActive returns whether or not a Pomodoro is active. A Pomodoro which
+// starts in the future, such as one which is scheduled, is not active yet.
 func (p *Pomodoro) IsActive() bool {
-	return !p.IsInactive() && !p.IsDone()
+	return !p.IsInactive() && !p.IsDone() && !clockNow(p.Clock).Before(p.StartTime)
+}
+
+// IsScheduled returns whether or not a Pomodoro is Scheduled to start in the
+// future.
+func (p *Pomodoro) IsScheduled() bool {
+	return p.Scheduled && clockNow(p.Clock).Before(p.StartTime)
+}
+
+// IsCompleted returns whether or not a Pomodoro was recorded with a non-zero
+// duration and was not cancelled or only scheduled.
+func (p *Pomodoro) IsCompleted() bool {
+	return p.Duration > 0 && !p.Cancelled && !p.Scheduled
 }
 
 // IsDone returns whether or not a Pomodoro was active and is now done.
@@ -158,7 +554,7 @@ func (p *Pomodoro) IsDone() bool {
 	if p.IsInactive() {
 		return false
 	}
-	return timeFunc().After(p.EndTime())
+	return clockNow(p.Clock).After(p.EndTime())
 }
 
 // IsInactive returns whether or not a Pomodoro is empty/not set/etc.
@@ -168,19 +564,185 @@ func (p *Pomodoro) IsInactive() bool {
 
 // Remaining returns the remaining duration of the Pomodoro.
 func (p *Pomodoro) Remaining() time.Duration {
+	return p.RemainingAt(clockNow(p.Clock))
+}
+
+// RemainingAt returns the remaining duration of the Pomodoro as of t, which is
+// negative if t is after it ends, and zero if the Pomodoro is inactive.
+func (p *Pomodoro) RemainingAt(t time.Time) time.Duration {
 	if p.IsInactive() {
 		return time.Duration(0)
 	}
 
-	return p.EndTime().Sub(timeFunc())
+	return p.EndTime().Sub(t)
 }
 
 // RemainingMinutes returns the remaining duration of the Pomodoro in minutes.
-// Partial minutes are rounded up and down normally, so that there are 25
-// minutes remaining for 30 seconds after the Pomodoro starts, and 0 for 30
-// seconds before it completes.
+// By default, partial minutes are rounded up and down normally, so that there
+// are 25 minutes remaining for 30 seconds after the Pomodoro starts, and 0 for
+// 30 seconds before it completes. See Rounding for other policies.
 func (p *Pomodoro) RemainingMinutes() int {
-	return round(p.Remaining().Minutes())
+	return p.Rounding.Minutes(p.Remaining())
+}
+
+// RemainingParts returns the remaining duration of the Pomodoro split into
+// whole minutes and seconds, with partial seconds truncated. Once the
+// Pomodoro is overtime, both parts are zero or negative, so that 90 seconds
+// over is -1 minutes and -30 seconds.
+func (p *Pomodoro) RemainingParts() (minutes, seconds int) {
+	total := int(p.Remaining() / time.Second)
+	return total / 60, total % 60
+}
+
+// PercentComplete returns how much of the Pomodoro's duration has elapsed,
+// from 0 to 100. It is 0 when inactive and 100 once done.
+func (p *Pomodoro) PercentComplete() float64 {
+	if p.IsInactive() {
+		return 0
+	}
+
+	if p.Duration <= 0 || p.IsDone() {
+		return 100
+	}
+
+	elapsed := clockNow(p.Clock).Sub(p.StartTime)
+	return math.Max(0, math.Min(100, 100*float64(elapsed)/float64(p.Duration)))
+}
+
+// ProgressBar returns a bar of width '#' and '-' characters in brackets
+// showing the PercentComplete, such as "[####------]".
+func (p *Pomodoro) ProgressBar(width int) string {
+	return p.ProgressBarRunes(width, '#', '-')
+}
+
+// ProgressBarRunes is like ProgressBar, but with the given runes for the
+// filled and empty parts of the bar. Partially filled positions are shown as
+// empty, so the bar is only full once the Pomodoro is done.
+func (p *Pomodoro) ProgressBarRunes(width int, fill rune, empty rune) string {
+	if width < 0 {
+		width = 0
+	}
+
+	filled := int(math.Floor(p.PercentComplete() / 100 * float64(width)))
+
+	return "[" + strings.Repeat(string(fill), filled) + strings.Repeat(string(empty), width-filled) + "]"
+}
+
+// splitTags splits tags which were split by commas when decoded from logfmt by
+// the separator instead. It returns the tags unchanged if the separator is
+// empty or a comma.
+func splitTags(tags []string, separator string) []string {
+	if separator == "" || separator == "," || len(tags) == 0 {
+		return tags
+	}
+
+	return strings.Split(strings.Join(tags, ","), separator)
+}
+
+// normalizeTags rewrites a tags value made of individually quoted tags, such
+// as tags="deep work",coding, into a single quoted value which the logfmt
+// decoder can split. Other attributes are returned unchanged.
+func normalizeTags(b []byte) ([]byte, error) {
+	inQuote := false
+
+	for i := 0; i < len(b); i++ {
+		switch {
+		case inQuote && b[i] == '\\':
+			i++
+		case b[i] == '"':
+			inQuote = !inQuote
+		case !inQuote && (i == 0 || b[i-1] == ' ') && bytes.HasPrefix(b[i:], []byte("tags=")):
+			start := i + len("tags=")
+			tags, n, ok := scanQuotedTags(b[start:])
+			if !ok {
+				return b, nil
+			}
+
+			value, err := logfmt.MarshalKeyvals("tags", strings.Join(tags, ","))
+			if err != nil {
+				return nil, err
+			}
+
+			normalized := append([]byte{}, b[:i]...)
+			normalized = append(normalized, value...)
+			return append(normalized, b[start+n:]...), nil
+		}
+	}
+
+	return b, nil
+}
+
+// scanQuotedTags scans comma-separated tags, each of which may be quoted, from
+// the start of a value. It returns the tags and the length of the value, and
+// whether the value has more than one tag with at least one of them quoted.
+func scanQuotedTags(b []byte) (tags []string, n int, ok bool) {
+	quoted := false
+
+	for {
+		if n < len(b) && b[n] == '"' {
+			end := n + 1
+			for end < len(b) && b[end] != '"' {
+				if b[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(b) {
+				return nil, 0, false
+			}
+
+			tag, err := strconv.Unquote(string(b[n : end+1]))
+			if err != nil {
+				return nil, 0, false
+			}
+
+			tags = append(tags, tag)
+			quoted = true
+			n = end + 1
+		} else {
+			end := n
+			for end < len(b) && b[end] != ',' && b[end] != ' ' && b[end] != '"' {
+				end++
+			}
+
+			tags = append(tags, string(b[n:end]))
+			n = end
+		}
+
+		if n < len(b) && b[n] == ',' {
+			n++
+			continue
+		}
+
+		break
+	}
+
+	if n < len(b) && b[n] != ' ' {
+		return nil, 0, false
+	}
+
+	return tags, n, quoted && len(tags) > 1
+}
+
+func copyStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append([]string{}, s...)
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
 }
 
 func bytesAllWhitespace(b []byte) bool {
@@ -14,6 +14,7 @@ func Test_PomodoroInterfaces(t *testing.T) {
 	var _ encoding.TextMarshaler = Pomodoro{}
 	var _ encoding.TextUnmarshaler = &Pomodoro{}
 	var _ json.Marshaler = Pomodoro{}
+	var _ json.Unmarshaler = &Pomodoro{}
 }
 
 func TestPomodoro_MarshalJSON(t *testing.T) {
@@ -26,10 +27,28 @@ func TestPomodoro_MarshalJSON(t *testing.T) {
 	b, err := p.MarshalJSON()
 	assert.Nil(t, err)
 	assert.Equal(t,
-		`{"start_time":"2016-06-14T12:00:00Z","description":"A description","duration":25,"tags":["a","b"]}`,
+		`{"start_time":"2016-06-14T12:00:00Z","description":"A description","duration":25,"end_time":"2016-06-14T12:25:00Z","tags":["a","b"]}`,
 		string(b))
 }
 
+func TestPomodoro_MarshalJSON_planned(t *testing.T) {
+	p := &Pomodoro{
+		StartTime:       time.Date(2016, 06, 14, 12, 0, 0, 0, time.UTC),
+		Duration:        20 * time.Minute,
+		PlannedDuration: 25 * time.Minute,
+	}
+	b, err := p.MarshalJSON()
+	require.Nil(t, err)
+	assert.Equal(t,
+		`{"start_time":"2016-06-14T12:00:00Z","description":"","duration":20,"planned_duration":25,"end_time":"2016-06-14T12:20:00Z","tags":null}`,
+		string(b))
+
+	o := &Pomodoro{}
+	require.Nil(t, o.UnmarshalJSON(b))
+	assert.Equal(t, 25*time.Minute, o.PlannedDuration)
+	assert.Equal(t, 20*time.Minute, o.Duration)
+}
+
 func TestPomodoro_MarshalText(t *testing.T) {
 	p := &Pomodoro{
 		StartTime:   time.Date(2016, 06, 14, 12, 0, 0, 0, time.UTC),
@@ -44,6 +63,34 @@ func TestPomodoro_MarshalText(t *testing.T) {
 		string(b))
 }
 
+func TestPomodoro_AttributesText(t *testing.T) {
+	p := &Pomodoro{
+		Duration:    25 * time.Minute,
+		Tags:        []string{"a", "b"},
+		Description: "A description",
+	}
+	b, err := p.AttributesText()
+	assert.Nil(t, err)
+	assert.Equal(t, "description=\"A description\" duration=25 tags=a,b", string(b))
+}
+
+func TestPomodoro_MarshalText_zeroDuration(t *testing.T) {
+	template := &Pomodoro{StartTime: fakeTime(), Description: "template"}
+
+	b, err := template.MarshalText()
+	require.Nil(t, err)
+	assert.Equal(t, "2016-06-14T12:34:56-04:00 description=template", string(b))
+
+	p := NewPomodoro()
+	require.Nil(t, p.UnmarshalText(b))
+	assert.Equal(t, DefaultSettings.DefaultPomodoroDuration, p.Duration)
+
+	p = EmptyPomodoro()
+	require.Nil(t, p.UnmarshalText(b))
+	p.ApplySettings(&Settings{DefaultPomodoroDuration: 30 * time.Minute})
+	assert.Equal(t, 30*time.Minute, p.Duration)
+}
+
 func Test_Matches(t *testing.T) {
 	timestamp, err := time.Parse(TimeFormat, "2026-06-14T12:34:56-04:00")
 	require.Nil(t, err)
@@ -134,6 +181,246 @@ func Test_UnmarshalText_singleLineAttributes(t *testing.T) {
 	assert.Equal(t, expected, p)
 }
 
+func Test_MarshalText_controlCharacters(t *testing.T) {
+	p := &Pomodoro{
+		StartTime:   fakeTime(),
+		Description: "pasted\nfrom\r\nsomewhere\tand\x00more",
+		Notes:       "line one\nline two",
+		Duration:    25 * time.Minute,
+	}
+
+	b, err := p.MarshalText()
+	require.Nil(t, err)
+	assert.NotContains(t, string(b), "\n")
+	assert.NotContains(t, string(b), "\r")
+	assert.NotContains(t, string(b), "\t")
+
+	parsed := &Pomodoro{}
+	require.Nil(t, parsed.UnmarshalText(b))
+	assert.Equal(t, p.Description, parsed.Description)
+	assert.Equal(t, p.Notes, parsed.Notes)
+}
+
+func Test_UnmarshalText_notes(t *testing.T) {
+	startTime, err := time.Parse(TimeFormat, "2026-06-14T12:34:56-04:00")
+	require.Nil(t, err)
+
+	expected := &Pomodoro{
+		StartTime:   startTime,
+		Description: "writing",
+		Notes:       `felt "focused", then distracted`,
+		Duration:    25 * time.Minute,
+	}
+
+	b, err := expected.MarshalText()
+	require.Nil(t, err)
+	assert.Equal(t,
+		`2026-06-14T12:34:56-04:00 description=writing notes="felt \"focused\", then distracted" duration=25`,
+		string(b))
+
+	p := &Pomodoro{}
+	require.Nil(t, p.UnmarshalText(b))
+	assert.Equal(t, expected, p)
+}
+
+func Test_UnmarshalText_color(t *testing.T) {
+	startTime, err := time.Parse(TimeFormat, "2026-06-14T12:34:56-04:00")
+	require.Nil(t, err)
+
+	expected := &Pomodoro{
+		StartTime: startTime,
+		Duration:  25 * time.Minute,
+		Tags:      []string{"study"},
+		Color:     "#ff6347",
+	}
+
+	b, err := expected.MarshalText()
+	require.Nil(t, err)
+	assert.Equal(t,
+		`2026-06-14T12:34:56-04:00 duration=25 tags=study color=#ff6347`,
+		string(b))
+
+	p := &Pomodoro{}
+	require.Nil(t, p.UnmarshalText(b))
+	assert.Equal(t, expected, p)
+
+	b, err = json.Marshal(expected)
+	require.Nil(t, err)
+	assert.Contains(t, string(b), `"color":"#ff6347"`)
+
+	expected.Color = ""
+	b, err = json.Marshal(expected)
+	require.Nil(t, err)
+	assert.NotContains(t, string(b), `color`)
+}
+
+func Test_UnmarshalText_tagSeparator(t *testing.T) {
+	startTime, err := time.Parse(TimeFormat, "2026-06-14T12:34:56-04:00")
+	require.Nil(t, err)
+
+	for separator, text := range map[string]string{
+		"/": `2026-06-14T12:34:56-04:00 duration=25 tags="client, inc/deep work"`,
+		" ": `2026-06-14T12:34:56-04:00 duration=25 tags="client,inc deep-work"`,
+	} {
+		tags := []string{"client, inc", "deep work"}
+		if separator == " " {
+			tags = []string{"client,inc", "deep-work"}
+		}
+
+		expected := &Pomodoro{
+			StartTime:    startTime,
+			Duration:     25 * time.Minute,
+			Tags:         tags,
+			TagSeparator: separator,
+		}
+
+		b, err := expected.MarshalText()
+		require.Nil(t, err)
+		assert.Equal(t, text, string(b))
+
+		p := &Pomodoro{TagSeparator: separator}
+		require.Nil(t, p.UnmarshalText(b))
+		assert.Equal(t, expected, p)
+	}
+}
+
+func Test_UnmarshalText_quotedTags(t *testing.T) {
+	tags := []string{"deep work", "coding"}
+
+	for _, text := range []string{
+		`2016-06-14T12:34:56-04:00 tags="deep work",coding duration=25`,
+		`2016-06-14T12:34:56-04:00 duration=25 tags="deep work","coding"`,
+		`2016-06-14T12:34:56-04:00 duration=25 tags="deep work,coding"`,
+	} {
+		p := &Pomodoro{}
+		require.Nil(t, p.UnmarshalText([]byte(text)))
+		assert.Equal(t, tags, p.Tags, text)
+		assert.Equal(t, 25*time.Minute, p.Duration, text)
+	}
+
+	p := &Pomodoro{}
+	require.Nil(t, p.UnmarshalText([]byte(`2016-06-14T12:34:56-04:00 description="tags=\"a b\",c" tags=x`)))
+	assert.Equal(t, `tags="a b",c`, p.Description)
+	assert.Equal(t, []string{"x"}, p.Tags)
+
+	b, err := (&Pomodoro{StartTime: fakeTime(), Tags: tags}).MarshalText()
+	require.Nil(t, err)
+	p = &Pomodoro{}
+	require.Nil(t, p.UnmarshalText(b))
+	assert.Equal(t, tags, p.Tags)
+}
+
+func Test_UnmarshalText_cancelled(t *testing.T) {
+	startTime, err := time.Parse(TimeFormat, "2026-06-14T12:34:56-04:00")
+	require.Nil(t, err)
+
+	expected := &Pomodoro{
+		StartTime: startTime,
+		Duration:  10 * time.Minute,
+		Cancelled: true,
+		Reason:    "meeting",
+	}
+
+	b, err := expected.MarshalText()
+	require.Nil(t, err)
+	assert.Equal(t,
+		`2026-06-14T12:34:56-04:00 duration=10 reason=meeting cancelled=true`,
+		string(b))
+
+	p := &Pomodoro{}
+	require.Nil(t, p.UnmarshalText(b))
+	assert.Equal(t, expected, p)
+
+	p = &Pomodoro{}
+	require.Nil(t, p.UnmarshalText([]byte(`2026-06-14T12:34:56-04:00 cancelled`)))
+	assert.True(t, p.Cancelled)
+
+	p = &Pomodoro{}
+	assert.NotNil(t, p.UnmarshalText([]byte(`2026-06-14T12:34:56-04:00 cancelled=maybe`)))
+}
+
+func Test_UnmarshalText_planned(t *testing.T) {
+	p := &Pomodoro{}
+	require.Nil(t, p.UnmarshalText([]byte(`2016-06-14T12:34:56-04:00 duration=20 planned=25`)))
+	assert.Equal(t, 20*time.Minute, p.Duration)
+	assert.Equal(t, 25*time.Minute, p.PlannedDuration)
+
+	b, err := p.MarshalText()
+	require.Nil(t, err)
+	assert.Equal(t, `2016-06-14T12:34:56-04:00 duration=20 planned=25`, string(b))
+}
+
+func Test_UnmarshalText_plannedZero(t *testing.T) {
+	p := NewPomodoro()
+	require.Nil(t, p.UnmarshalText([]byte(`2016-06-14T12:34:56-04:00 planned=25`)))
+	assert.Equal(t, time.Duration(0), p.Duration)
+	assert.Equal(t, 25*time.Minute, p.Planned())
+
+	b, err := p.MarshalText()
+	require.Nil(t, err)
+	assert.Equal(t, `2016-06-14T12:34:56-04:00 planned=25`, string(b))
+}
+
+func TestPomodoro_Planned(t *testing.T) {
+	p := &Pomodoro{Duration: 20 * time.Minute}
+	assert.Equal(t, 20*time.Minute, p.Planned())
+
+	p.PlannedDuration = 25 * time.Minute
+	assert.Equal(t, 25*time.Minute, p.Planned())
+}
+
+func TestPomodoro_String_omitsNotes(t *testing.T) {
+	p := Pomodoro{
+		StartTime:   time.Date(2016, 06, 14, 12, 0, 0, 0, time.UTC),
+		Description: "writing",
+		Notes:       "a long reflection",
+		Duration:    25 * time.Minute,
+	}
+
+	assert.Equal(t, "2016-06-14T12:00:00Z description=writing duration=25", p.String())
+	assert.Equal(t, "a long reflection", p.Notes)
+}
+
+func TestPomodoro_Overlaps(t *testing.T) {
+	start := time.Date(2016, 06, 14, 12, 0, 0, 0, time.UTC)
+	p := &Pomodoro{StartTime: start, Duration: 25 * time.Minute}
+
+	assert.True(t, p.Overlaps(p))
+	assert.True(t, p.Overlaps(&Pomodoro{StartTime: start.Add(24 * time.Minute), Duration: 25 * time.Minute}))
+	assert.True(t, p.Overlaps(&Pomodoro{StartTime: start.Add(-24 * time.Minute), Duration: 25 * time.Minute}))
+	assert.True(t, p.Overlaps(&Pomodoro{StartTime: start.Add(time.Minute), Duration: time.Minute}))
+	assert.True(t, p.Overlaps(&Pomodoro{StartTime: start.Add(-time.Hour), Duration: 2 * time.Hour}))
+
+	assert.False(t, p.Overlaps(&Pomodoro{StartTime: start.Add(25 * time.Minute), Duration: 25 * time.Minute}))
+	assert.False(t, p.Overlaps(&Pomodoro{StartTime: start.Add(-25 * time.Minute), Duration: 25 * time.Minute}))
+	assert.False(t, p.Overlaps(&Pomodoro{StartTime: start.Add(time.Hour), Duration: 25 * time.Minute}))
+}
+
+func Test_UnmarshalText_fallbackTimeFormats(t *testing.T) {
+	local := time.Date(2016, 06, 14, 12, 34, 56, 0, time.Local)
+	edt := time.FixedZone("EDT", -4*60*60)
+
+	for text, expected := range map[string]time.Time{
+		"2016-06-14T12:34:56 duration=20":       local,
+		"2016-06-14T12:34 duration=20":          local.Add(-56 * time.Second),
+		"2016-06-14 12:34:56-04:00 duration=20": time.Date(2016, 06, 14, 12, 34, 56, 0, edt),
+		"2016-06-14 12:34:56 duration=20":       local,
+		"2016-06-14 12:34 duration=20":          local.Add(-56 * time.Second),
+	} {
+		p := &Pomodoro{}
+		require.Nil(t, p.UnmarshalText([]byte(text)), text)
+		assert.True(t, expected.Equal(p.StartTime), text)
+		assert.Equal(t, 20*time.Minute, p.Duration, text)
+	}
+
+	p := &Pomodoro{}
+	require.Nil(t, p.UnmarshalText([]byte("2016-06-14 12:34")))
+	assert.True(t, local.Add(-56*time.Second).Equal(p.StartTime))
+
+	p = &Pomodoro{}
+	assert.NotNil(t, p.UnmarshalText([]byte("June 14 duration=20")))
+}
+
 func Test_UnmarshalText_empty(t *testing.T) {
 	p := &Pomodoro{}
 	err := p.UnmarshalText([]byte(``))
@@ -167,6 +454,72 @@ func Test_ApplySettings_empty(t *testing.T) {
 
 	assert.Equal(t, p.Duration, 25*time.Minute)
 	assert.Equal(t, p.Tags, []string{"work"})
+	assert.Equal(t, p.Rounding, Rounding(""))
+
+	s.Rounding = RoundCeil
+	p.ApplySettings(s)
+
+	assert.Equal(t, p.Rounding, RoundCeil)
+}
+
+func Test_ApplySettings_copiesTags(t *testing.T) {
+	s := &Settings{DefaultTags: []string{"work"}}
+
+	p := &Pomodoro{}
+	p.ApplySettings(s)
+	p.Tags[0] = "play"
+
+	assert.Equal(t, []string{"work"}, s.DefaultTags)
+}
+
+func TestPomodoro_SetDuration(t *testing.T) {
+	p := &Pomodoro{Duration: 25 * time.Minute}
+
+	assert.Equal(t, ErrInvalidDuration, p.SetDuration(-time.Minute))
+	assert.Equal(t, ErrDurationTooLong, p.SetDuration(MaxDuration+time.Second))
+	assert.Equal(t, 25*time.Minute, p.Duration)
+
+	require.Nil(t, p.SetDuration(MaxDuration))
+	assert.Equal(t, MaxDuration, p.Duration)
+
+	require.Nil(t, p.SetDuration(0))
+	assert.Equal(t, time.Duration(0), p.Duration)
+}
+
+func TestPomodoro_TagsContain(t *testing.T) {
+	p := &Pomodoro{Tags: []string{"work", "deep work"}}
+	assert.True(t, p.TagsContain("work"))
+	assert.True(t, p.TagsContain("deep work"))
+	assert.False(t, p.TagsContain("Work"))
+	assert.False(t, p.TagsContain("deep"))
+	assert.False(t, (&Pomodoro{}).TagsContain(""))
+}
+
+func TestPomodoro_Equal(t *testing.T) {
+	timestamp, err := time.Parse(TimeFormat, "2026-06-14T12:34:56-04:00")
+	require.Nil(t, err)
+
+	a := &Pomodoro{StartTime: timestamp, Duration: 25 * time.Minute, Tags: []string{}}
+	b := &Pomodoro{StartTime: timestamp.UTC(), Duration: 25 * time.Minute, Rounding: RoundFloor}
+	assert.True(t, a.Equal(b))
+
+	b.Notes = "notes"
+	assert.False(t, a.Equal(b))
+
+	b = a.Copy()
+	b.Tags = []string{"work"}
+	assert.False(t, a.Equal(b))
+}
+
+func Test_Copy(t *testing.T) {
+	p := &Pomodoro{Description: "original", Tags: []string{"work"}}
+
+	c := p.Copy()
+	c.Description = "copy"
+	c.Tags[0] = "play"
+
+	assert.Equal(t, "original", p.Description)
+	assert.Equal(t, []string{"work"}, p.Tags)
 }
 
 func Test_ApplySettings_existing(t *testing.T) {
@@ -199,6 +552,22 @@ func Test_DurationMinutes(t *testing.T) {
 	assert.Equal(t, 29, p.DurationMinutes())
 }
 
+func Test_DurationMinutes_rounding(t *testing.T) {
+	p := Pomodoro{Duration: 29*time.Minute + 30*time.Second}
+
+	p.Rounding = RoundFloor
+	assert.Equal(t, 29, p.DurationMinutes())
+
+	p.Rounding = RoundCeil
+	assert.Equal(t, 30, p.DurationMinutes())
+
+	p.Duration = 29*time.Minute + 1*time.Second
+	assert.Equal(t, 30, p.DurationMinutes())
+
+	p.Rounding = RoundHalfUp
+	assert.Equal(t, 29, p.DurationMinutes())
+}
+
 func Test_EndTime(t *testing.T) {
 	start, err := time.Parse(TimeFormat, "2026-06-14T12:34:56-04:00")
 	require.Nil(t, err)
@@ -209,9 +578,30 @@ func Test_EndTime(t *testing.T) {
 	assert.Equal(t, expected, p.EndTime())
 }
 
-func Test_IsActive(t *testing.T) {
-	timeFunc = time.Now
+func Test_IsOnDay(t *testing.T) {
+	edt := time.FixedZone("EDT", -4*60*60)
+	p := Pomodoro{StartTime: time.Date(2016, 06, 14, 23, 59, 59, 0, edt)}
+
+	assert.True(t, p.IsOnDay(time.Date(2016, 06, 14, 0, 0, 0, 0, edt)))
+	assert.True(t, p.IsOnDay(time.Date(2016, 06, 14, 23, 59, 59, 0, edt)))
+	assert.False(t, p.IsOnDay(time.Date(2016, 06, 15, 0, 0, 0, 0, edt)))
+	assert.False(t, p.IsOnDay(time.Date(2016, 06, 13, 23, 59, 59, 0, edt)))
+
+	assert.True(t, p.IsOnDay(time.Date(2016, 06, 15, 12, 0, 0, 0, time.UTC)))
+	assert.False(t, p.IsOnDay(time.Date(2016, 06, 14, 12, 0, 0, 0, time.UTC)))
+}
+
+func Test_IsToday(t *testing.T) {
+	now := time.Date(2016, 06, 14, 0, 0, 1, 0, time.UTC)
 
+	p := Pomodoro{StartTime: now.Add(-time.Second)}
+	assert.True(t, p.IsToday(now))
+
+	p = Pomodoro{StartTime: now.Add(-2 * time.Second)}
+	assert.False(t, p.IsToday(now))
+}
+
+func Test_IsActive(t *testing.T) {
 	p := NewPomodoro()
 	p.Duration = 25 * time.Minute
 
@@ -220,19 +610,26 @@ func Test_IsActive(t *testing.T) {
 		25 * time.Minute: false,
 		26 * time.Minute: false,
 		time.Hour:        false,
-		-time.Hour:       true,
+		-time.Hour:       false,
 		0 * time.Second:  true,
 	}
 
 	for duration, expected := range cases {
-		p.StartTime = timeFunc().Add(-duration)
+		p.StartTime = time.Now().Add(-duration)
 		assert.Equal(t, expected, p.IsActive(), duration.String())
 	}
 }
 
-func Test_IsDone(t *testing.T) {
-	timeFunc = time.Now
+func Test_IsCompleted(t *testing.T) {
+	assert.False(t, EmptyPomodoro().IsCompleted())
+	assert.True(t, NewPomodoro().IsCompleted())
+
+	p := NewPomodoro()
+	p.Cancelled = true
+	assert.False(t, p.IsCompleted())
+}
 
+func Test_IsDone(t *testing.T) {
 	p := NewPomodoro()
 	p.Duration = 25 * time.Minute
 
@@ -246,7 +643,7 @@ func Test_IsDone(t *testing.T) {
 	}
 
 	for duration, expected := range cases {
-		p.StartTime = timeFunc().Add(-duration)
+		p.StartTime = time.Now().Add(-duration)
 		assert.Equal(t, expected, p.IsDone(), duration.String())
 	}
 }
@@ -267,9 +664,8 @@ func Test_IsInactive_false(t *testing.T) {
 }
 
 func Test_Remaining(t *testing.T) {
-	timeFunc = time.Now
-
 	p := NewPomodoro()
+	p.Clock = NewFakeClock(fakeTime())
 	p.Duration = 25 * time.Minute
 
 	assert.Equal(t, float64(0), p.Remaining().Seconds())
@@ -283,11 +679,38 @@ func Test_Remaining(t *testing.T) {
 	}
 
 	for duration, expected := range cases {
-		p.StartTime = timeFunc().Add(-duration)
+		p.StartTime = fakeTime().Add(-duration)
 		assert.InDelta(t, expected.Seconds(), p.Remaining().Seconds(), 1)
 	}
 }
 
+func Test_RemainingAt(t *testing.T) {
+	p := &Pomodoro{StartTime: fakeTime(), Duration: 25 * time.Minute}
+
+	assert.Equal(t, 25*time.Minute, p.RemainingAt(fakeTime()))
+	assert.Equal(t, 15*time.Minute, p.RemainingAt(fakeTime().Add(10*time.Minute)))
+	assert.Equal(t, -5*time.Minute, p.RemainingAt(fakeTime().Add(30*time.Minute)))
+	assert.Equal(t, time.Duration(0), EmptyPomodoro().RemainingAt(fakeTime()))
+}
+
+func Test_RemainingMinutes_rounding(t *testing.T) {
+	p := NewPomodoro()
+	p.Clock = NewFakeClock(fakeTime())
+	p.StartTime = fakeTime().Add(-30 * time.Second)
+
+	p.Rounding = RoundFloor
+	assert.Equal(t, 24, p.RemainingMinutes())
+
+	p.Rounding = RoundCeil
+	assert.Equal(t, 25, p.RemainingMinutes())
+
+	p.StartTime = fakeTime().Add(-24*time.Minute - 59*time.Second)
+	assert.Equal(t, 1, p.RemainingMinutes())
+
+	p.Rounding = RoundFloor
+	assert.Equal(t, 0, p.RemainingMinutes())
+}
+
 func Test_RemainingMinutes(t *testing.T) {
 	p := NewPomodoro()
 	p.Duration = 25 * time.Minute
@@ -308,7 +731,62 @@ func Test_RemainingMinutes(t *testing.T) {
 	}
 
 	for duration, expected := range cases {
-		p.StartTime = timeFunc().Add(-duration)
+		p.StartTime = time.Now().Add(-duration)
 		assert.Equal(t, expected, p.RemainingMinutes())
 	}
 }
+
+func Test_RemainingParts(t *testing.T) {
+	p := NewPomodoro()
+	p.Clock = NewFakeClock(fakeTime())
+
+	minutes, seconds := p.RemainingParts()
+	assert.Equal(t, 0, minutes)
+	assert.Equal(t, 0, seconds)
+
+	cases := map[time.Duration][2]int{
+		0:                               {25, 0},
+		30 * time.Second:                {24, 30},
+		500 * time.Millisecond:          {24, 59},
+		24*time.Minute + 30*time.Second: {0, 30},
+		24*time.Minute + 59*time.Second: {0, 1},
+		25 * time.Minute:                {0, 0},
+		25*time.Minute + 30*time.Second: {0, -30},
+		26 * time.Minute:                {-1, 0},
+		26*time.Minute + 30*time.Second: {-1, -30},
+	}
+
+	for elapsed, expected := range cases {
+		p.StartTime = fakeTime().Add(-elapsed)
+		minutes, seconds := p.RemainingParts()
+		assert.Equal(t, expected, [2]int{minutes, seconds}, elapsed.String())
+	}
+}
+
+func TestPomodoro_ProgressBar(t *testing.T) {
+	clock := NewFakeClock(fakeTime())
+
+	p := &Pomodoro{Clock: clock}
+	assert.Equal(t, 0.0, p.PercentComplete())
+	assert.Equal(t, "[----------]", p.ProgressBar(10))
+
+	p = &Pomodoro{StartTime: fakeTime(), Duration: 20 * time.Minute, Clock: clock}
+	assert.Equal(t, 0.0, p.PercentComplete())
+	assert.Equal(t, "[----------]", p.ProgressBar(10))
+
+	p.StartTime = fakeTime().Add(-5 * time.Minute)
+	assert.Equal(t, 25.0, p.PercentComplete())
+	assert.Equal(t, "[##--------]", p.ProgressBar(10))
+	assert.Equal(t, "[#---]", p.ProgressBar(4))
+	assert.Equal(t, "[▓▓░░░░░░░░]", p.ProgressBarRunes(10, '▓', '░'))
+
+	p.StartTime = fakeTime().Add(-20*time.Minute + time.Second)
+	assert.Equal(t, "[#########-]", p.ProgressBar(10))
+
+	p.StartTime = fakeTime().Add(-time.Hour)
+	assert.Equal(t, 100.0, p.PercentComplete())
+	assert.Equal(t, "[##########]", p.ProgressBar(10))
+
+	assert.Equal(t, "[]", p.ProgressBar(0))
+	assert.Equal(t, "[]", p.ProgressBar(-1))
+}
@@ -0,0 +1,69 @@
+package openpomodoro
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ReadOnly(t *testing.T) {
+	clock := NewFakeClock(fakeTime())
+
+	dir := fixture("")
+	w, err := NewClient(dir, WithClock(clock))
+	require.Nil(t, err)
+	require.Nil(t, w.Log(&Pomodoro{}, fakeTime().Add(-time.Hour), 25*time.Minute))
+	require.Nil(t, w.Start(&Pomodoro{Description: "running"}))
+
+	current, err := ioutil.ReadFile(w.CurrentPath())
+	require.Nil(t, err)
+	history, err := ioutil.ReadFile(w.HistoryPath())
+	require.Nil(t, err)
+
+	c, err := NewClient(dir, WithReadOnly(), WithClock(clock))
+	require.Nil(t, err)
+
+	mutators := map[string]func() error{
+		"Start":            func() error { return c.Start(&Pomodoro{}) },
+		"Finish":           c.Finish,
+		"Cancel":           c.Cancel,
+		"CancelWithReason": func() error { return c.CancelWithReason("meeting") },
+		"Clear":            c.Clear,
+		"Log": func() error {
+			return c.Log(&Pomodoro{}, fakeTime().Add(-3*time.Hour), 25*time.Minute)
+		},
+		"Defer":   func() error { return c.Defer(time.Minute) },
+		"Journal": func() error { return c.Journal("note") },
+		"Migrate": c.Migrate,
+		"MoveTo": func() error {
+			_, err := c.MoveTo(fixture(""))
+			return err
+		},
+	}
+
+	for name, mutate := range mutators {
+		assert.Equal(t, ErrReadOnly, mutate(), name)
+	}
+
+	b, err := ioutil.ReadFile(c.CurrentPath())
+	require.Nil(t, err)
+	assert.Equal(t, string(current), string(b))
+
+	b, err = ioutil.ReadFile(c.HistoryPath())
+	require.Nil(t, err)
+	assert.Equal(t, string(history), string(b))
+
+	p, err := c.Pomodoro()
+	require.Nil(t, err)
+	assert.Equal(t, "running", p.Description)
+
+	h, err := c.History()
+	require.Nil(t, err)
+	assert.Equal(t, 2, h.Count())
+
+	_, err = c.Settings()
+	assert.Nil(t, err)
+}
@@ -0,0 +1,39 @@
+package openpomodoro
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned when no Pomodoro in the history has the given start
+// time.
+var ErrNotFound = errors.New("no Pomodoro found with that start time")
+
+// StartLike starts a new Pomodoro now like Start, with the description, tags,
+// and planned duration of the Pomodoro in the history which started at
+// startTime, and returns it. It returns ErrNotFound if there is no such
+// Pomodoro.
+func (c *Client) StartLike(startTime time.Time) (*Pomodoro, error) {
+	h, err := c.History()
+	if err != nil {
+		return nil, err
+	}
+
+	i := h.index(&Pomodoro{StartTime: startTime})
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+
+	template := h.Pomodoros[i]
+	p := &Pomodoro{
+		Description: template.Description,
+		Tags:        copyStrings(template.Tags),
+		Duration:    template.Planned(),
+	}
+
+	if err := c.Start(p); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
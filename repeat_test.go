@@ -0,0 +1,37 @@
+package openpomodoro
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_StartLike(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	start := fakeTime().Add(-time.Hour)
+	require.Nil(t, c.Log(&Pomodoro{
+		Description:     "write report",
+		Tags:            []string{"work"},
+		PlannedDuration: 50 * time.Minute,
+		Notes:           "not copied",
+	}, start, 40*time.Minute))
+
+	p, err := c.StartLike(start)
+	require.Nil(t, err)
+	assert.Equal(t, "write report", p.Description)
+	assert.Equal(t, []string{"work"}, p.Tags)
+	assert.Equal(t, 50*time.Minute, p.Duration)
+	assert.Empty(t, p.Notes)
+
+	current, err := c.Pomodoro()
+	require.Nil(t, err)
+	assert.True(t, current.StartTime.Equal(fakeTime()))
+	assert.Equal(t, "write report", current.Description)
+
+	_, err = c.StartLike(start.Add(time.Minute))
+	assert.Equal(t, ErrNotFound, err)
+}
@@ -0,0 +1,36 @@
+package openpomodoro
+
+import (
+	"math"
+	"time"
+)
+
+// Rounding is a policy for rounding durations to whole minutes.
+type Rounding string
+
+const (
+	// RoundHalfUp rounds partial minutes up or down normally, so that 29m30s
+	// is 30 minutes. This is the default.
+	RoundHalfUp Rounding = "round"
+
+	// RoundFloor rounds partial minutes down, so that a minute is only
+	// counted once it has fully elapsed.
+	RoundFloor Rounding = "floor"
+
+	// RoundCeil rounds partial minutes up, so that a minute is counted until
+	// it has fully elapsed.
+	RoundCeil Rounding = "ceil"
+)
+
+// Minutes returns the duration in whole minutes according to the policy. An
+// empty or unknown policy is treated as RoundHalfUp.
+func (r Rounding) Minutes(d time.Duration) int {
+	switch r {
+	case RoundFloor:
+		return int(math.Floor(d.Minutes()))
+	case RoundCeil:
+		return int(math.Ceil(d.Minutes()))
+	default:
+		return round(d.Minutes())
+	}
+}
@@ -0,0 +1,47 @@
+package openpomodoro
+
+import (
+	"sort"
+	"strings"
+)
+
+// RowTimeFormat is the layout of the start and end times in Rows.
+const RowTimeFormat = "15:04"
+
+// Row is a Pomodoro formatted for display in a table.
+type Row struct {
+	Date        string
+	Start       string
+	End         string
+	Duration    string
+	Description string
+	Tags        string
+}
+
+// Rows sorts the collection and returns a Row for each Pomodoro, with times
+// in RowTimeFormat. See RowsFormat.
+func (h *History) Rows() []Row {
+	return h.RowsFormat(RowTimeFormat)
+}
+
+// RowsFormat sorts the collection and returns a Row for each Pomodoro, with
+// start and end times in the given layout. Dates are in DateFormat, and times
+// are in the location each Pomodoro was recorded in. Durations are like "25m"
+// or "1h30m", and tags are joined with a comma and a space.
+func (h *History) RowsFormat(layout string) []Row {
+	sort.Sort(h)
+
+	rows := make([]Row, len(h.Pomodoros))
+	for i, p := range h.Pomodoros {
+		rows[i] = Row{
+			Date:        p.StartTime.Format(DateFormat),
+			Start:       p.StartTime.Format(layout),
+			End:         p.EndTime().Format(layout),
+			Duration:    formatDuration(p.Duration),
+			Description: p.Description,
+			Tags:        strings.Join(p.Tags, ", "),
+		}
+	}
+
+	return rows
+}
@@ -0,0 +1,23 @@
+package openpomodoro
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistory_Rows(t *testing.T) {
+	h := &History{Pomodoros: []*Pomodoro{
+		{StartTime: fakeTime().Add(time.Hour), Duration: 90 * time.Minute, Description: "writing", Tags: []string{"work", "deep work"}},
+		{StartTime: fakeTime(), Duration: 25 * time.Minute},
+	}}
+
+	assert.Equal(t, []Row{
+		{Date: "2016-06-14", Start: "12:34", End: "12:59", Duration: "25m"},
+		{Date: "2016-06-14", Start: "13:34", End: "15:04", Duration: "1h30m", Description: "writing", Tags: "work, deep work"},
+	}, h.Rows())
+
+	assert.Equal(t, "1:34PM", h.RowsFormat(time.Kitchen)[1].Start)
+	assert.Equal(t, []Row{}, empty.Rows())
+}
@@ -0,0 +1,88 @@
+package openpomodoro
+
+import (
+	"io/ioutil"
+	"os"
+	"sort"
+	"time"
+)
+
+// Schedule records a Pomodoro in the `schedule` file as Scheduled to start at
+// the given time, with configured defaults, without changing the `current` or
+// `history` files. It returns ErrOverlap if it would overlap another scheduled
+// Pomodoro, and the errors of Validate for an invalid duration.
+func (c *Client) Schedule(p *Pomodoro, start time.Time) error {
+	s, err := c.Settings()
+	if err != nil {
+		return err
+	}
+
+	p.StartTime = start
+	p.Scheduled = true
+	p.ApplySettings(s)
+
+	if err := p.Validate(s); err != nil {
+		return err
+	}
+
+	schedule, err := c.Scheduled()
+	if err != nil {
+		return err
+	}
+
+	for _, existing := range schedule.Pomodoros {
+		if p.Overlaps(existing) {
+			return ErrOverlap
+		}
+	}
+
+	schedule.Pomodoros = append(schedule.Pomodoros, p)
+	sort.Sort(schedule)
+
+	b, err := schedule.MarshalText()
+	if err != nil {
+		return err
+	}
+
+	if err := c.ensureDirectory(); err != nil {
+		return err
+	}
+
+	return c.writeFile(c.SchedulePath(), b)
+}
+
+// Scheduled returns the Pomodoros from the `schedule` file, oldest first.
+func (c *Client) Scheduled() (*History, error) {
+	s, err := c.readSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := ioutil.ReadFile(c.SchedulePath())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	h := parseHistory(b, s, c.Clock)
+	sort.Sort(h)
+
+	return h, nil
+}
+
+// DueNow returns the Pomodoro from the `schedule` file which should be running
+// at now, because it starts at or before now and ends after it, or nil if
+// there is none.
+func (c *Client) DueNow(now time.Time) (*Pomodoro, error) {
+	schedule, err := c.Scheduled()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range schedule.Pomodoros {
+		if !now.Before(p.StartTime) && now.Before(p.EndTime()) {
+			return p, nil
+		}
+	}
+
+	return nil, nil
+}
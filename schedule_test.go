@@ -0,0 +1,72 @@
+package openpomodoro
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Schedule(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	later := fakeTime().Add(time.Hour)
+	require.Nil(t, c.Schedule(&Pomodoro{Description: "later"}, later))
+	require.Nil(t, c.Schedule(&Pomodoro{Description: "soon"}, fakeTime().Add(10*time.Minute)))
+	assert.Equal(t, ErrOverlap, c.Schedule(&Pomodoro{}, later.Add(time.Minute)))
+
+	schedule, err := c.Scheduled()
+	require.Nil(t, err)
+	require.Equal(t, 2, schedule.Count())
+	assert.Equal(t, "soon", schedule.Pomodoros[0].Description)
+	assert.True(t, schedule.Pomodoros[0].Scheduled)
+	assert.Equal(t, 25*time.Minute, schedule.Pomodoros[0].Duration)
+
+	assertHistoryLength(0)(t, c, "")
+
+	due, err := c.DueNow(fakeTime())
+	require.Nil(t, err)
+	assert.Nil(t, due)
+
+	due, err = c.DueNow(later.Add(5 * time.Minute))
+	require.Nil(t, err)
+	require.NotNil(t, due)
+	assert.Equal(t, "later", due.Description)
+
+	due, err = c.DueNow(later.Add(25 * time.Minute))
+	require.Nil(t, err)
+	assert.Nil(t, due)
+}
+
+func TestPomodoro_IsScheduled(t *testing.T) {
+	p := &Pomodoro{
+		StartTime: fakeTime().Add(time.Minute),
+		Duration:  25 * time.Minute,
+		Scheduled: true,
+		Clock:     NewFakeClock(fakeTime()),
+	}
+	assert.True(t, p.IsScheduled())
+	assert.False(t, p.IsActive())
+	assert.False(t, p.IsDone())
+	assert.False(t, p.IsCompleted())
+
+	b, err := p.MarshalText()
+	require.Nil(t, err)
+	assert.Equal(t, "2016-06-14T12:35:56-04:00 duration=25 scheduled=true", string(b))
+
+	o := &Pomodoro{}
+	require.Nil(t, o.UnmarshalText(b))
+	assert.True(t, o.Scheduled)
+
+	p.StartTime = fakeTime()
+	assert.False(t, p.IsScheduled())
+	assert.True(t, p.IsActive())
+
+	p.Scheduled = false
+	p.StartTime = fakeTime().Add(time.Minute)
+	assert.False(t, p.IsScheduled())
+	assert.False(t, p.IsActive())
+	assert.False(t, p.IsDone())
+}
@@ -2,6 +2,11 @@ package openpomodoro
 
 import (
 	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/justincampbell/go-logfmt"
@@ -9,11 +14,29 @@ import (
 
 // Settings is a collection of user settings, which can come from a file, env
 // var, or set from the client program.
+//
+// The `settings` file is usually logfmt, where durations are in minutes unless
+// they have a unit. It may instead be a JSON object with the same keys, where
+// durations are Go duration strings such as "25m" or "1h30m". It is read as
+// YAML with the same keys as JSON if its name ends in .yaml or .yml, or if it
+// does not exist but a `settings.yaml` file next to it does.
 type Settings struct {
 	DailyGoal               int           `logfmt:"daily_goal"`
 	DefaultBreakDuration    time.Duration `logfmt:"default_break_duration,m"`
 	DefaultPomodoroDuration time.Duration `logfmt:"default_pomodoro_duration,m"`
 	DefaultTags             []string      `logfmt:"default_tags"`
+	KeepCancelled           bool          `logfmt:"keep_cancelled"`
+	Rounding                Rounding      `logfmt:"rounding"`
+	DayStartOffset          time.Duration `logfmt:"day_start_offset,m"`
+	MinDuration             time.Duration `logfmt:"min_duration,m"`
+	TagRules                TagRules
+	TagSeparator            string        `logfmt:"tag_separator"`
+	WorkdayStart            time.Duration `logfmt:"workday_start,m"`
+	WorkdayEnd              time.Duration `logfmt:"workday_end,m"`
+	WorkDays                Weekdays
+	Holidays                []string      `logfmt:"holidays"`
+	RoundFinishTo           time.Duration `logfmt:"round_finish_to,m"`
+	LongBreakInterval       int           `logfmt:"long_break_interval"`
 }
 
 // DefaultSettings are used as a starting point before settings are overridden
@@ -23,6 +46,30 @@ var DefaultSettings = Settings{
 	DefaultBreakDuration:    5 * time.Minute,
 	DefaultPomodoroDuration: 25 * time.Minute,
 	DefaultTags:             []string{},
+	KeepCancelled:           false,
+	Rounding:                RoundHalfUp,
+	DayStartOffset:          0,
+	MinDuration:             0,
+	TagRules:                TagRules{},
+	TagSeparator:            ",",
+	WorkdayStart:            9 * time.Hour,
+	WorkdayEnd:              17 * time.Hour,
+	WorkDays:                Weekdays{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday},
+	Holidays:                []string{},
+	RoundFinishTo:           0,
+	LongBreakInterval:       4,
+}
+
+// Copy returns a deep copy of the settings.
+func (s *Settings) Copy() *Settings {
+	c := *s
+	c.DefaultTags = copyStrings(s.DefaultTags)
+	c.TagRules = s.TagRules.Copy()
+	if s.WorkDays != nil {
+		c.WorkDays = append(Weekdays{}, s.WorkDays...)
+	}
+	c.Holidays = copyStrings(s.Holidays)
+	return &c
 }
 
 // SetDefaults fills in settings values from another setting struct if the
@@ -41,12 +88,300 @@ func (s *Settings) SetDefaults(d *Settings) {
 	}
 
 	if len(s.DefaultTags) == 0 {
-		s.DefaultTags = d.DefaultTags
+		s.DefaultTags = copyStrings(d.DefaultTags)
+	}
+
+	if !s.KeepCancelled {
+		s.KeepCancelled = d.KeepCancelled
+	}
+
+	if s.Rounding == "" {
+		s.Rounding = d.Rounding
+	}
+
+	if s.DayStartOffset == 0 {
+		s.DayStartOffset = d.DayStartOffset
+	}
+
+	if s.MinDuration == 0 {
+		s.MinDuration = d.MinDuration
+	}
+
+	if len(s.TagRules) == 0 {
+		s.TagRules = d.TagRules.Copy()
+	}
+
+	if s.TagSeparator == "" {
+		s.TagSeparator = d.TagSeparator
+	}
+
+	if s.WorkdayStart == 0 {
+		s.WorkdayStart = d.WorkdayStart
+	}
+
+	if s.WorkdayEnd == 0 {
+		s.WorkdayEnd = d.WorkdayEnd
+	}
+
+	if len(s.WorkDays) == 0 {
+		s.WorkDays = append(Weekdays{}, d.WorkDays...)
+	}
+
+	if len(s.Holidays) == 0 {
+		s.Holidays = copyStrings(d.Holidays)
+	}
+
+	if s.RoundFinishTo == 0 {
+		s.RoundFinishTo = d.RoundFinishTo
+	}
+
+	if s.LongBreakInterval == 0 {
+		s.LongBreakInterval = d.LongBreakInterval
+	}
+}
+
+// Day returns the date which the given time belongs to, considering the
+// DayStartOffset. With an offset of 4 hours, 1am belongs to the previous day.
+func (s *Settings) Day(t time.Time) time.Time {
+	y, m, d := t.Add(-s.DayStartOffset).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// WorkdayElapsed returns the fraction of the workday window, from 0 to 1,
+// which has elapsed at t on the day t belongs to. The window is from
+// WorkdayStart to WorkdayEnd after midnight, and continues past midnight if
+// the end is before the start. Since a zero WorkdayStart is replaced by the
+// default, a negative one means midnight.
+func (s *Settings) WorkdayElapsed(t time.Time) float64 {
+	day := s.Day(t)
+	start := day
+	if s.WorkdayStart > 0 {
+		start = day.Add(s.WorkdayStart)
+	}
+	end := day.Add(s.WorkdayEnd)
+	if !end.After(start) {
+		end = end.AddDate(0, 0, 1)
+	}
+
+	switch {
+	case !t.After(start):
+		return 0
+	case !t.Before(end):
+		return 1
+	default:
+		return float64(t.Sub(start)) / float64(end.Sub(start))
 	}
 }
 
 // UnmarshalText updates settings by parsing each key/value pair in logfmt.
+// The default tags are split by the tag separator in the same settings.
 func (s *Settings) UnmarshalText(b []byte) error {
 	b = bytes.Replace(b, charNewline, charSpace, -1)
-	return logfmt.Unmarshal(b, s)
+
+	b, err := normalizeMinutes(b,
+		"default_break_duration",
+		"default_pomodoro_duration",
+		"day_start_offset",
+		"min_duration",
+		"workday_start",
+		"workday_end",
+		"round_finish_to",
+	)
+	if err != nil {
+		return err
+	}
+
+	err = logfmt.Unmarshal(b, s)
+	if err != nil {
+		return err
+	}
+
+	s.DefaultTags = splitTags(s.DefaultTags, s.TagSeparator)
+
+	if err := validateHolidays(s.Holidays); err != nil {
+		return err
+	}
+
+	return s.unmarshalTextValues(b)
+}
+
+// unmarshalTextValues sets the values of settings which the logfmt decoder
+// cannot handle itself, from the tag_rules and work_days keys.
+func (s *Settings) unmarshalTextValues(b []byte) error {
+	values := map[string]encoding.TextUnmarshaler{
+		"tag_rules": &s.TagRules,
+		"work_days": &s.WorkDays,
+	}
+
+	d := logfmt.NewDecoder(bytes.NewReader(b))
+	if !d.ScanRecord() {
+		return d.Err()
+	}
+
+	for d.ScanKeyval() {
+		key := string(d.Key())
+		value, ok := values[key]
+		if !ok {
+			continue
+		}
+
+		if err := value.UnmarshalText(d.Value()); err != nil {
+			return fmt.Errorf("Error while parsing %s: %s", key, err)
+		}
+	}
+
+	return d.Err()
+}
+
+// settingsJSON is the JSON and YAML form of Settings, with durations as
+// strings.
+type settingsJSON struct {
+	DailyGoal               int      `json:"daily_goal,omitempty" yaml:"daily_goal"`
+	DefaultBreakDuration    string   `json:"default_break_duration,omitempty" yaml:"default_break_duration"`
+	DefaultPomodoroDuration string   `json:"default_pomodoro_duration,omitempty" yaml:"default_pomodoro_duration"`
+	DefaultTags             []string `json:"default_tags,omitempty" yaml:"default_tags"`
+	KeepCancelled           bool     `json:"keep_cancelled,omitempty" yaml:"keep_cancelled"`
+	Rounding                Rounding `json:"rounding,omitempty" yaml:"rounding"`
+	DayStartOffset          string   `json:"day_start_offset,omitempty" yaml:"day_start_offset"`
+	MinDuration             string   `json:"min_duration,omitempty" yaml:"min_duration"`
+	TagRules                TagRules `json:"tag_rules,omitempty" yaml:"tag_rules"`
+	TagSeparator            string   `json:"tag_separator,omitempty" yaml:"tag_separator"`
+	WorkdayStart            string   `json:"workday_start,omitempty" yaml:"workday_start"`
+	WorkdayEnd              string   `json:"workday_end,omitempty" yaml:"workday_end"`
+	WorkDays                Weekdays `json:"work_days,omitempty" yaml:"work_days"`
+	Holidays                []string `json:"holidays,omitempty" yaml:"holidays"`
+	RoundFinishTo           string   `json:"round_finish_to,omitempty" yaml:"round_finish_to"`
+	LongBreakInterval       int      `json:"long_break_interval,omitempty" yaml:"long_break_interval"`
+}
+
+// settingsKeys returns the name of every setting.
+func settingsKeys() []string {
+	t := reflect.TypeOf(settingsJSON{})
+	keys := make([]string, t.NumField())
+	for i := range keys {
+		keys[i] = strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+	}
+	return keys
+}
+
+// setKeys returns the names of the settings which are set, meaning they would
+// not be replaced by SetDefaults.
+func (s Settings) setKeys() (map[string]bool, error) {
+	b, err := s.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(b, &values); err != nil {
+		return nil, err
+	}
+
+	set := map[string]bool{}
+	for key := range values {
+		set[key] = true
+	}
+	return set, nil
+}
+
+// durations returns pointers to each pair of duration fields.
+func (j *settingsJSON) durations(s *Settings) map[*string]*time.Duration {
+	return map[*string]*time.Duration{
+		&j.DefaultBreakDuration:    &s.DefaultBreakDuration,
+		&j.DefaultPomodoroDuration: &s.DefaultPomodoroDuration,
+		&j.DayStartOffset:          &s.DayStartOffset,
+		&j.MinDuration:             &s.MinDuration,
+		&j.WorkdayStart:            &s.WorkdayStart,
+		&j.WorkdayEnd:              &s.WorkdayEnd,
+		&j.RoundFinishTo:           &s.RoundFinishTo,
+	}
+}
+
+// MarshalJSON implements json.Marshaler. Durations are encoded as Go duration
+// strings, and unset values are omitted.
+func (s Settings) MarshalJSON() ([]byte, error) {
+	j := settingsJSON{
+		DailyGoal:         s.DailyGoal,
+		DefaultTags:       s.DefaultTags,
+		KeepCancelled:     s.KeepCancelled,
+		Rounding:          s.Rounding,
+		TagRules:          s.TagRules,
+		TagSeparator:      s.TagSeparator,
+		WorkDays:          s.WorkDays,
+		Holidays:          s.Holidays,
+		LongBreakInterval: s.LongBreakInterval,
+	}
+
+	for str, d := range j.durations(&s) {
+		if *d != 0 {
+			*str = formatDuration(*d)
+		}
+	}
+
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Durations may be Go duration
+// strings, or plain numbers of minutes in a string.
+func (s *Settings) UnmarshalJSON(b []byte) error {
+	var j settingsJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+
+	return s.fromJSON(j)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler, with the same keys and durations
+// as JSON.
+func (s *Settings) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var j settingsJSON
+	if err := unmarshal(&j); err != nil {
+		return err
+	}
+
+	return s.fromJSON(j)
+}
+
+func (s *Settings) fromJSON(j settingsJSON) error {
+	s.DailyGoal = j.DailyGoal
+	s.DefaultTags = j.DefaultTags
+	s.KeepCancelled = j.KeepCancelled
+	s.Rounding = j.Rounding
+	s.TagRules = j.TagRules
+	s.TagSeparator = j.TagSeparator
+	s.WorkDays = j.WorkDays
+	s.Holidays = j.Holidays
+	s.LongBreakInterval = j.LongBreakInterval
+
+	if err := validateHolidays(s.Holidays); err != nil {
+		return err
+	}
+
+	for str, d := range j.durations(s) {
+		if *str == "" {
+			continue
+		}
+
+		duration, err := parseMinutes(*str)
+		if err != nil {
+			return err
+		}
+		*d = duration
+	}
+
+	return nil
+}
+
+// formatDuration formats a duration like time.Duration.String, but without
+// trailing zero units, so that 25 minutes is "25m" rather than "25m0s".
+func formatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
 }
@@ -2,6 +2,8 @@ package openpomodoro
 
 import (
 	"encoding"
+	"encoding/json"
+	"io/ioutil"
 	"testing"
 	"time"
 
@@ -27,6 +29,18 @@ func Test_SetDefaults_filled(t *testing.T) {
 		DefaultBreakDuration:    10 * time.Minute,
 		DefaultPomodoroDuration: 20 * time.Minute,
 		DefaultTags:             []string{"work"},
+		KeepCancelled:           true,
+		Rounding:                RoundFloor,
+		DayStartOffset:          4 * time.Hour,
+		MinDuration:             5 * time.Minute,
+		TagRules:                TagRules{{Start: 9 * time.Hour, End: 17 * time.Hour, Tags: []string{"work"}}},
+		TagSeparator:            "/",
+		WorkdayStart:            8 * time.Hour,
+		WorkdayEnd:              16 * time.Hour,
+		WorkDays:                Weekdays{time.Monday, time.Friday},
+		Holidays:                []string{"2016-12-25"},
+		RoundFinishTo:           time.Minute,
+		LongBreakInterval:       3,
 	}
 
 	expected := &Settings{}
@@ -47,6 +61,15 @@ func Test_Settings_UnmarshalText(t *testing.T) {
 	  default_break_duration=10
 	  default_pomodoro_duration=20
 	  default_tags=billable,work
+	  keep_cancelled=true
+	  rounding=ceil
+	  day_start_offset=4h
+	  min_duration=5
+	  tag_rules="09:00-17:00=work;17:00-09:00=personal,home"
+	  workday_start=8h
+	  workday_end=16h30m
+	  work_days=mon,tue,wed,thu,friday
+	  holidays=2016-12-25,2017-01-01
 	`))
 	require.Nil(t, err)
 
@@ -54,4 +77,172 @@ func Test_Settings_UnmarshalText(t *testing.T) {
 	assert.Equal(t, 10*time.Minute, s.DefaultBreakDuration)
 	assert.Equal(t, 20*time.Minute, s.DefaultPomodoroDuration)
 	assert.Equal(t, []string{"billable", "work"}, s.DefaultTags)
+	assert.True(t, s.KeepCancelled)
+	assert.Equal(t, RoundCeil, s.Rounding)
+	assert.Equal(t, 4*time.Hour, s.DayStartOffset)
+	assert.Equal(t, 5*time.Minute, s.MinDuration)
+	assert.Equal(t, TagRules{
+		{Start: 9 * time.Hour, End: 17 * time.Hour, Tags: []string{"work"}},
+		{Start: 17 * time.Hour, End: 9 * time.Hour, Tags: []string{"personal", "home"}},
+	}, s.TagRules)
+	assert.Equal(t, 8*time.Hour, s.WorkdayStart)
+	assert.Equal(t, 16*time.Hour+30*time.Minute, s.WorkdayEnd)
+	assert.Equal(t, Weekdays{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}, s.WorkDays)
+	assert.Equal(t, []string{"2016-12-25", "2017-01-01"}, s.Holidays)
+
+	assert.NotNil(t, s.UnmarshalText([]byte("work_days=someday")))
+	assert.NotNil(t, s.UnmarshalText([]byte("holidays=christmas")))
+}
+
+func Test_Settings_UnmarshalText_tagSeparator(t *testing.T) {
+	s := &Settings{}
+	require.Nil(t, s.UnmarshalText([]byte(`default_tags="client, inc/billable" tag_separator=/`)))
+
+	assert.Equal(t, "/", s.TagSeparator)
+	assert.Equal(t, []string{"client, inc", "billable"}, s.DefaultTags)
+}
+
+func Test_Settings_WorkdayElapsed(t *testing.T) {
+	s := &Settings{WorkdayStart: 9 * time.Hour, WorkdayEnd: 17 * time.Hour}
+	at := func(hour, min int) time.Time {
+		return time.Date(2016, 06, 14, hour, min, 0, 0, time.UTC)
+	}
+
+	assert.Equal(t, 0.0, s.WorkdayElapsed(at(8, 0)))
+	assert.Equal(t, 0.0, s.WorkdayElapsed(at(9, 0)))
+	assert.Equal(t, 0.5, s.WorkdayElapsed(at(13, 0)))
+	assert.Equal(t, 1.0, s.WorkdayElapsed(at(17, 0)))
+	assert.Equal(t, 1.0, s.WorkdayElapsed(at(23, 0)))
+
+	s = &Settings{WorkdayStart: 22 * time.Hour, WorkdayEnd: 6 * time.Hour, DayStartOffset: 12 * time.Hour}
+	assert.Equal(t, 0.25, s.WorkdayElapsed(at(0, 0)))
+	assert.Equal(t, 0.0, s.WorkdayElapsed(at(21, 0)))
+
+	s = &Settings{}
+	require.Nil(t, s.UnmarshalText([]byte("workday_start=-1 workday_end=16h")))
+	s.SetDefaults(&DefaultSettings)
+	assert.Equal(t, 0.0, s.WorkdayElapsed(at(0, 0)))
+	assert.Equal(t, 0.5, s.WorkdayElapsed(at(8, 0)))
+}
+
+func Test_Settings_Day(t *testing.T) {
+	s := &Settings{DayStartOffset: 4 * time.Hour}
+
+	assert.Equal(t,
+		time.Date(2016, 06, 13, 0, 0, 0, 0, time.UTC),
+		s.Day(time.Date(2016, 06, 14, 3, 59, 59, 0, time.UTC)))
+	assert.Equal(t,
+		time.Date(2016, 06, 14, 0, 0, 0, 0, time.UTC),
+		s.Day(time.Date(2016, 06, 14, 4, 0, 0, 0, time.UTC)))
+
+	s.DayStartOffset = 0
+	assert.Equal(t,
+		time.Date(2016, 06, 14, 0, 0, 0, 0, time.UTC),
+		s.Day(time.Date(2016, 06, 14, 0, 0, 0, 0, time.UTC)))
+}
+
+func Test_Settings_JSON(t *testing.T) {
+	s := &Settings{
+		DailyGoal:               8,
+		DefaultBreakDuration:    5 * time.Minute,
+		DefaultPomodoroDuration: 25 * time.Minute,
+		DefaultTags:             []string{"work"},
+		DayStartOffset:          4*time.Hour + 30*time.Minute,
+		WorkdayStart:            9 * time.Hour,
+		TagRules:                TagRules{{Start: 9 * time.Hour, End: 17 * time.Hour, Tags: []string{"work"}}},
+		WorkDays:                Weekdays{time.Monday, time.Tuesday},
+		Holidays:                []string{"2016-12-25"},
+	}
+
+	b, err := json.Marshal(s)
+	require.Nil(t, err)
+	assert.Equal(t,
+		`{"daily_goal":8,"default_break_duration":"5m","default_pomodoro_duration":"25m","default_tags":["work"],"day_start_offset":"4h30m","tag_rules":"09:00-17:00=work","workday_start":"9h","work_days":"Mon,Tue","holidays":["2016-12-25"]}`,
+		string(b))
+
+	parsed := &Settings{}
+	require.Nil(t, json.Unmarshal(b, parsed))
+	assert.Equal(t, s, parsed)
+
+	assert.NotNil(t, json.Unmarshal([]byte(`{"min_duration":"soon"}`), parsed))
+}
+
+func Test_Settings_JSONFile(t *testing.T) {
+	text, err := NewClient(fixture(""))
+	require.Nil(t, err)
+	require.Nil(t, text.ensureDirectory())
+	require.Nil(t, ioutil.WriteFile(text.SettingsPath(), []byte(
+		"daily_goal=8 default_pomodoro_duration=20 default_tags=a,b keep_cancelled=true day_start_offset=4h min_duration=90s",
+	), FilePerm))
+
+	j, err := NewClient(fixture(""))
+	require.Nil(t, err)
+	require.Nil(t, j.ensureDirectory())
+	require.Nil(t, ioutil.WriteFile(j.SettingsPath(), []byte(`
+		{
+		  "daily_goal": 8,
+		  "default_pomodoro_duration": "20m",
+		  "default_tags": ["a", "b"],
+		  "keep_cancelled": true,
+		  "day_start_offset": "4h",
+		  "min_duration": "1m30s"
+		}
+	`), FilePerm))
+
+	expected, err := text.Settings()
+	require.Nil(t, err)
+
+	actual, err := j.Settings()
+	require.Nil(t, err)
+
+	assert.Equal(t, expected, actual)
+	assert.Equal(t, 20*time.Minute, actual.DefaultPomodoroDuration)
+	assert.Equal(t, 90*time.Second, actual.MinDuration)
+}
+
+func Test_Settings_YAMLFile(t *testing.T) {
+	settings := `
+daily_goal: 8
+default_pomodoro_duration: 20m
+default_tags: [a, b]
+keep_cancelled: true
+day_start_offset: 4h
+min_duration: 1m30s
+tag_rules: "09:00-17:00=work"
+workday_end: 1080
+`
+
+	fallback, err := NewClient(fixture(""))
+	require.Nil(t, err)
+	require.Nil(t, fallback.ensureDirectory())
+	require.Nil(t, ioutil.WriteFile(fallback.SettingsPath()+".yaml", []byte(settings), FilePerm))
+
+	named, err := NewClient(fixture(""), WithSettingsFile("pomodoro.yml"))
+	require.Nil(t, err)
+	require.Nil(t, named.ensureDirectory())
+	require.Nil(t, ioutil.WriteFile(named.SettingsPath(), []byte(settings), FilePerm))
+
+	for _, c := range []*Client{fallback, named} {
+		s, err := c.Settings()
+		require.Nil(t, err)
+
+		assert.Equal(t, 8, s.DailyGoal)
+		assert.Equal(t, 20*time.Minute, s.DefaultPomodoroDuration)
+		assert.Equal(t, DefaultSettings.DefaultBreakDuration, s.DefaultBreakDuration)
+		assert.Equal(t, []string{"a", "b"}, s.DefaultTags)
+		assert.True(t, s.KeepCancelled)
+		assert.Equal(t, 4*time.Hour, s.DayStartOffset)
+		assert.Equal(t, 90*time.Second, s.MinDuration)
+		assert.Equal(t, TagRules{{Start: 9 * time.Hour, End: 17 * time.Hour, Tags: []string{"work"}}}, s.TagRules)
+		assert.Equal(t, 18*time.Hour, s.WorkdayEnd)
+	}
+
+	require.Nil(t, ioutil.WriteFile(fallback.SettingsPath(), []byte("daily_goal=4"), FilePerm))
+	s, err := fallback.Settings()
+	require.Nil(t, err)
+	assert.Equal(t, 4, s.DailyGoal)
+
+	require.Nil(t, ioutil.WriteFile(named.SettingsPath(), []byte("min_duration: soon"), FilePerm))
+	_, err = named.Settings()
+	assert.NotNil(t, err)
 }
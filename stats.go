@@ -0,0 +1,84 @@
+package openpomodoro
+
+import "time"
+
+// Stats are common numbers for a report about a range of time.
+type Stats struct {
+	// Count is how many Pomodoros were started in the range, and Completed is
+	// how many of them were completed.
+	Count     int
+	Completed int
+
+	// Duration is the total duration of the Pomodoros, and AverageDuration is
+	// the mean, which is 0 if there are none.
+	Duration        time.Duration
+	AverageDuration time.Duration
+
+	// ActiveDays is how many days had at least one Pomodoro, and GoalMetDays
+	// is how many had at least the daily goal completed.
+	ActiveDays  int
+	GoalMetDays int
+
+	// Streak is how many days in a row the daily goal was met, up to the end
+	// of the range. It is only set by Client.Stats.
+	Streak int
+}
+
+// Stats returns the Stats for the Pomodoros started between the start and end
+// times, inclusive like Range, in a single pass over the collection. Days are
+// in end's location and begin at the DayStartOffset. GoalMetDays is 0 if goal
+// is not positive.
+func (h *History) Stats(start, end time.Time, goal int) *Stats {
+	stats := &Stats{}
+	completed := map[string]int{}
+	loc := end.Location()
+
+	for _, p := range h.Pomodoros {
+		if t := p.StartTime; t.Before(start) || t.After(end) {
+			continue
+		}
+
+		day := p.StartTime.In(loc).Add(-h.DayStartOffset).Format(DateFormat)
+		if _, ok := completed[day]; !ok {
+			completed[day] = 0
+			stats.ActiveDays++
+		}
+
+		stats.Count++
+		stats.Duration += p.Duration
+
+		if p.IsCompleted() {
+			stats.Completed++
+			completed[day]++
+			if completed[day] == goal {
+				stats.GoalMetDays++
+			}
+		}
+	}
+
+	if stats.Count > 0 {
+		stats.AverageDuration = stats.Duration / time.Duration(stats.Count)
+	}
+
+	return stats
+}
+
+// Stats returns the Stats for the Pomodoros in the `history` file started
+// between the start and end times, using the daily goal and work days from
+// settings.
+func (c *Client) Stats(start, end time.Time) (*Stats, error) {
+	s, err := c.Settings()
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := c.History()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := h.Stats(start, end, s.DailyGoal)
+	stats.Streak = h.Streak(s.DailyGoal, end, s.IsWorkDay)
+
+	return stats, nil
+}
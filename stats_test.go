@@ -0,0 +1,55 @@
+package openpomodoro
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory_Stats(t *testing.T) {
+	at := func(day, hour int) time.Time {
+		return time.Date(2016, 06, day, hour, 0, 0, 0, time.UTC)
+	}
+
+	h := &History{Pomodoros: []*Pomodoro{
+		{StartTime: at(12, 9), Duration: 25 * time.Minute},
+		{StartTime: at(13, 9), Duration: 25 * time.Minute},
+		{StartTime: at(13, 10), Duration: 25 * time.Minute},
+		{StartTime: at(14, 9), Duration: 10 * time.Minute, Cancelled: true},
+		{StartTime: at(14, 10), Duration: 25 * time.Minute},
+		{StartTime: at(14, 11), Duration: 25 * time.Minute},
+	}}
+
+	assert.Equal(t, &Stats{
+		Count:           5,
+		Completed:       4,
+		Duration:        110 * time.Minute,
+		AverageDuration: 22 * time.Minute,
+		ActiveDays:      2,
+		GoalMetDays:     2,
+	}, h.Stats(at(13, 0), at(15, 0), 2))
+
+	assert.Equal(t, 0, h.Stats(at(13, 0), at(15, 0), 0).GoalMetDays)
+	assert.Equal(t, &Stats{}, h.Stats(at(20, 0), at(21, 0), 2))
+}
+
+func TestClient_Stats(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	require.Nil(t, c.ensureDirectory())
+	require.Nil(t, ioutil.WriteFile(c.SettingsPath(), []byte("daily_goal=1"), FilePerm))
+
+	for days := 0; days < 3; days++ {
+		require.Nil(t, c.Log(&Pomodoro{}, fakeTime().AddDate(0, 0, -days).Add(-time.Hour), 25*time.Minute))
+	}
+
+	stats, err := c.Stats(fakeTime().AddDate(0, 0, -1).Add(-2*time.Hour), fakeTime())
+	require.Nil(t, err)
+	assert.Equal(t, 2, stats.Count)
+	assert.Equal(t, 2, stats.GoalMetDays)
+	assert.Equal(t, 3, stats.Streak)
+}
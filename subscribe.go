@@ -0,0 +1,49 @@
+package openpomodoro
+
+import (
+	"sync"
+	"time"
+)
+
+// Subscribe returns a channel which receives the current Status immediately,
+// and then every interval, along with a function which stops the updates and
+// closes the channel. Statuses which cannot be read are skipped. It is safe
+// to call the function more than once. A non-positive interval is treated as
+// one second.
+func (c *Client) Subscribe(interval time.Duration) (<-chan *Status, func()) {
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	statuses := make(chan *Status)
+	stop := make(chan struct{})
+
+	go func() {
+		defer close(statuses)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			status, err := c.status()
+			if err != nil {
+				debug("reading status: %s", err)
+			} else {
+				select {
+				case statuses <- status:
+				case <-stop:
+					return
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return statuses, func() { once.Do(func() { close(stop) }) }
+}
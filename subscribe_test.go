@@ -0,0 +1,51 @@
+package openpomodoro
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Subscribe(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	statuses, unsubscribe := c.Subscribe(time.Millisecond)
+
+	status := <-statuses
+	assert.False(t, status.Active)
+
+	require.Nil(t, c.Start(&Pomodoro{Description: "live"}))
+
+	for status = range statuses {
+		if status.Active {
+			break
+		}
+	}
+	assert.Equal(t, "live", status.Pomodoro.Description)
+	assert.Equal(t, 25*60, status.RemainingSeconds)
+
+	unsubscribe()
+	unsubscribe()
+
+	for range statuses {
+	}
+}
+
+func Test_Subscribe_nonPositiveInterval(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		statuses, unsubscribe := c.Subscribe(interval)
+
+		status := <-statuses
+		assert.False(t, status.Active)
+
+		unsubscribe()
+		for range statuses {
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package openpomodoro
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TagRule applies tags to Pomodoros started within a time of day range.
+type TagRule struct {
+	// Start is the time after midnight that the range begins, inclusive.
+	Start time.Duration
+
+	// End is the time after midnight that the range ends, exclusive. If it is
+	// before Start, the range continues past midnight. If it is the same as
+	// Start, the range covers the whole day.
+	End time.Duration
+
+	// Tags are the tags to apply.
+	Tags []string
+}
+
+// TagRules are a list of rules for tagging Pomodoros by time of day, where
+// the first matching rule is used.
+//
+// In text, each rule is written as start-end=tags with 24-hour times, and
+// rules are separated by semicolons, such as
+// "09:00-17:00=work;17:00-09:00=personal,home".
+type TagRules []TagRule
+
+// Matches returns whether or not the time of day of t, in t's location, is
+// within the rule's range.
+func (r TagRule) Matches(t time.Time) bool {
+	y, m, d := t.Date()
+	clock := t.Sub(time.Date(y, m, d, 0, 0, 0, 0, t.Location()))
+
+	switch {
+	case r.Start == r.End:
+		return true
+	case r.Start < r.End:
+		return clock >= r.Start && clock < r.End
+	default:
+		return clock >= r.Start || clock < r.End
+	}
+}
+
+// Tags returns a copy of the tags of the first rule matching t, or nil if no
+// rule matches.
+func (rs TagRules) Tags(t time.Time) []string {
+	for _, r := range rs {
+		if r.Matches(t) {
+			return copyStrings(r.Tags)
+		}
+	}
+
+	return nil
+}
+
+// Copy returns a deep copy of the rules.
+func (rs TagRules) Copy() TagRules {
+	if rs == nil {
+		return nil
+	}
+
+	c := make(TagRules, len(rs))
+	for i, r := range rs {
+		c[i] = r
+		c[i].Tags = copyStrings(r.Tags)
+	}
+	return c
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (rs TagRules) MarshalText() ([]byte, error) {
+	rules := make([]string, len(rs))
+	for i, r := range rs {
+		rules[i] = fmt.Sprintf("%s-%s=%s",
+			formatClock(r.Start), formatClock(r.End), strings.Join(r.Tags, ","))
+	}
+
+	return []byte(strings.Join(rules, ";")), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (rs *TagRules) UnmarshalText(b []byte) error {
+	rules := TagRules{}
+
+	for _, rule := range strings.Split(string(b), ";") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+
+		parts := strings.SplitN(rule, "=", 2)
+		times := strings.SplitN(parts[0], "-", 2)
+		if len(parts) != 2 || len(times) != 2 {
+			return fmt.Errorf("invalid tag rule %q", rule)
+		}
+
+		start, err := parseClock(times[0])
+		if err != nil {
+			return err
+		}
+
+		end, err := parseClock(times[1])
+		if err != nil {
+			return err
+		}
+
+		rules = append(rules, TagRule{
+			Start: start,
+			End:   end,
+			Tags:  strings.Split(parts[1], ","),
+		})
+	}
+
+	*rs = rules
+	return nil
+}
+
+// parseClock parses a 24-hour time of day such as 09:30 into the time after
+// midnight.
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func formatClock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
+}
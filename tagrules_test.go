@@ -0,0 +1,77 @@
+package openpomodoro
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagRules_Tags(t *testing.T) {
+	rules := TagRules{}
+	require.Nil(t, rules.UnmarshalText([]byte("09:00-17:00=work;17:00-09:00=personal")))
+
+	at := func(hour, min int) time.Time {
+		return time.Date(2016, 06, 14, hour, min, 0, 0, time.UTC)
+	}
+
+	assert.Equal(t, []string{"personal"}, rules.Tags(at(8, 59)))
+	assert.Equal(t, []string{"work"}, rules.Tags(at(9, 0)))
+	assert.Equal(t, []string{"work"}, rules.Tags(at(16, 59)))
+	assert.Equal(t, []string{"personal"}, rules.Tags(at(17, 0)))
+	assert.Equal(t, []string{"personal"}, rules.Tags(at(0, 0)))
+
+	rules = TagRules{{Start: 12 * time.Hour, End: 13 * time.Hour, Tags: []string{"lunch"}}}
+	assert.Nil(t, rules.Tags(at(13, 0)))
+	assert.Nil(t, TagRules{}.Tags(at(12, 0)))
+
+	rules = TagRules{{Tags: []string{"always"}}}
+	assert.Equal(t, []string{"always"}, rules.Tags(at(23, 59)))
+}
+
+func TestTagRules_Text(t *testing.T) {
+	rules := TagRules{
+		{Start: 9 * time.Hour, End: 17*time.Hour + 30*time.Minute, Tags: []string{"work", "billable"}},
+		{Start: 17*time.Hour + 30*time.Minute, End: 9 * time.Hour, Tags: []string{"personal"}},
+	}
+
+	b, err := rules.MarshalText()
+	require.Nil(t, err)
+	assert.Equal(t, "09:00-17:30=work,billable;17:30-09:00=personal", string(b))
+
+	parsed := TagRules{}
+	require.Nil(t, parsed.UnmarshalText(b))
+	assert.Equal(t, rules, parsed)
+
+	b, err = json.Marshal(rules)
+	require.Nil(t, err)
+	assert.Equal(t, `"09:00-17:30=work,billable;17:30-09:00=personal"`, string(b))
+
+	parsed = TagRules{}
+	require.Nil(t, json.Unmarshal(b, &parsed))
+	assert.Equal(t, rules, parsed)
+
+	assert.NotNil(t, parsed.UnmarshalText([]byte("09:00=work")))
+	assert.NotNil(t, parsed.UnmarshalText([]byte("9am-5pm=work")))
+}
+
+func Test_ApplySettings_tagRules(t *testing.T) {
+	s := &Settings{
+		DefaultTags: []string{"default"},
+		TagRules:    TagRules{{Start: 9 * time.Hour, End: 17 * time.Hour, Tags: []string{"work"}}},
+	}
+
+	p := &Pomodoro{StartTime: time.Date(2016, 06, 14, 10, 0, 0, 0, time.UTC)}
+	p.ApplySettings(s)
+	assert.Equal(t, []string{"work"}, p.Tags)
+
+	p = &Pomodoro{StartTime: time.Date(2016, 06, 14, 18, 0, 0, 0, time.UTC)}
+	p.ApplySettings(s)
+	assert.Equal(t, []string{"default"}, p.Tags)
+
+	p = &Pomodoro{StartTime: time.Date(2016, 06, 14, 10, 0, 0, 0, time.UTC), Tags: []string{"mine"}}
+	p.ApplySettings(s)
+	assert.Equal(t, []string{"mine"}, p.Tags)
+}
@@ -0,0 +1,115 @@
+package openpomodoro
+
+import (
+	"bytes"
+	"context"
+	"time"
+)
+
+// tailInterval is how often TailHistory re-reads the `history` file.
+var tailInterval = time.Second
+
+// TailHistory returns a channel which receives each Pomodoro added to the
+// `history` file after it is called, like `tail -f`. The file is re-read
+// periodically, and only lines after those already read are parsed. If the
+// file is rewritten instead of appended to, such as by Log or TrimHistory, it
+// is read again from the top. A Pomodoro is only sent once, even if its line
+// is rewritten later, such as when it is finished. The channel is closed when
+// the context is cancelled.
+func (c *Client) TailHistory(ctx context.Context) (<-chan *Pomodoro, error) {
+	s, err := c.readSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := c.RawHistory()
+	if err != nil {
+		return nil, err
+	}
+
+	t := &historyTail{settings: s, clock: c.Clock, seen: map[int64]bool{}}
+	t.read(b)
+
+	pomodoros := make(chan *Pomodoro)
+
+	go func() {
+		defer close(pomodoros)
+
+		ticker := time.NewTicker(tailInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+
+			b, err := c.RawHistory()
+			if err != nil {
+				debug("reading history: %s", err)
+				continue
+			}
+
+			for _, p := range t.read(b) {
+				select {
+				case pomodoros <- p:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return pomodoros, nil
+}
+
+// historyTail tracks how much of the `history` file has been read.
+type historyTail struct {
+	settings *Settings
+	clock    Clock
+
+	// offset is the end of the last complete line read.
+	offset int
+
+	// last is the last complete line read, to notice if the file was
+	// rewritten.
+	last []byte
+
+	// seen are the start times of the Pomodoros read, in Unix nanoseconds.
+	seen map[int64]bool
+}
+
+// read returns the Pomodoros in complete lines of the file after those
+// already read.
+func (t *historyTail) read(b []byte) []*Pomodoro {
+	start := t.offset
+	rewritten := len(b) < t.offset || !bytes.Equal(b[t.offset-len(t.last):t.offset], t.last)
+	if rewritten {
+		start = 0
+	}
+
+	end := bytes.LastIndex(b, charNewline) + 1
+	if end <= start {
+		if rewritten {
+			t.offset, t.last = 0, nil
+		}
+		return nil
+	}
+
+	chunk := b[start:end]
+	t.offset = end
+	t.last = append([]byte{}, chunk[bytes.LastIndex(chunk[:len(chunk)-1], charNewline)+1:]...)
+
+	var added []*Pomodoro
+	for _, p := range parseHistory(chunk, t.settings, t.clock).Pomodoros {
+		key := p.StartTime.UnixNano()
+		if t.seen[key] {
+			continue
+		}
+		t.seen[key] = true
+		added = append(added, p)
+	}
+
+	return added
+}
@@ -0,0 +1,59 @@
+package openpomodoro
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TailHistory(t *testing.T) {
+	defer func(d time.Duration) { tailInterval = d }(tailInterval)
+	tailInterval = time.Millisecond
+
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	require.Nil(t, c.Log(&Pomodoro{Description: "old"}, fakeTime().Add(-2*time.Hour), 25*time.Minute))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pomodoros, err := c.TailHistory(ctx)
+	require.Nil(t, err)
+
+	require.Nil(t, c.Start(&Pomodoro{Description: "new"}))
+	assert.Equal(t, "new", (<-pomodoros).Description)
+
+	// Trimming rewrites the file without adding anything new, and logging
+	// rewrites it with an earlier Pomodoro inserted.
+	require.Nil(t, c.TrimHistory(1))
+	time.Sleep(10 * tailInterval)
+	require.Nil(t, c.Log(&Pomodoro{Description: "logged"}, fakeTime().Add(-time.Hour), 25*time.Minute))
+	assert.Equal(t, "logged", (<-pomodoros).Description)
+
+	timeTravel(25*time.Minute)(t, c, "")
+	require.Nil(t, c.Finish())
+	require.Nil(t, c.Start(&Pomodoro{Description: "next"}))
+	assert.Equal(t, "next", (<-pomodoros).Description)
+
+	cancel()
+	for range pomodoros {
+	}
+}
+
+func Test_historyTail_partialLine(t *testing.T) {
+	tail := &historyTail{settings: &Settings{}, seen: map[int64]bool{}}
+
+	added := tail.read([]byte("2016-06-14T12:34:56-04:00 description=a\n2016-06-14T13:"))
+	require.Len(t, added, 1)
+	assert.Equal(t, "a", added[0].Description)
+
+	added = tail.read([]byte("2016-06-14T12:34:56-04:00 description=a\n2016-06-14T13:34:56-04:00 description=b\n"))
+	require.Len(t, added, 1)
+	assert.Equal(t, "b", added[0].Description)
+
+	added = tail.read([]byte("2016-06-14T13:34:56-04:00 description=b\n2016-06-14T14:34:56-04:00 description=c\n"))
+	require.Len(t, added, 1)
+	assert.Equal(t, "c", added[0].Description)
+}
@@ -0,0 +1,108 @@
+package openpomodoro
+
+import (
+	"sort"
+	"time"
+)
+
+// SegmentKind is what a Segment of a timeline covers.
+type SegmentKind string
+
+const (
+	// SegmentPomodoro is a Pomodoro, including a cancelled one.
+	SegmentPomodoro SegmentKind = "pomodoro"
+
+	// SegmentBreak is the break after a completed Pomodoro.
+	SegmentBreak SegmentKind = "break"
+
+	// SegmentIdle is any other time.
+	SegmentIdle SegmentKind = "idle"
+)
+
+// Segment is a span of time in a timeline, from Start up to End.
+type Segment struct {
+	Start time.Time
+	End   time.Time
+	Kind  SegmentKind
+
+	// Pomodoro is the Pomodoro of a SegmentPomodoro, or the one which the
+	// break follows for a SegmentBreak.
+	Pomodoro *Pomodoro
+}
+
+// Timeline sorts the collection and returns segments covering the time from
+// start up to end without gaps, in order. Pomodoros are cut off at the edges
+// of the range, and a Pomodoro which overlaps an earlier one only covers the
+// time after it. Breaks are not recorded, so the time between Pomodoros is
+// idle. Use Client.Timeline to include breaks.
+func (h *History) Timeline(start, end time.Time) []Segment {
+	return h.timeline(start, end, 0)
+}
+
+// timeline is Timeline, with up to breakDuration after each completed Pomodoro
+// as a break.
+func (h *History) timeline(start, end time.Time, breakDuration time.Duration) []Segment {
+	sort.Sort(h)
+
+	var segments []Segment
+	var prev *Pomodoro
+	cursor := start
+
+	fill := func(to time.Time) {
+		if prev != nil && breakDuration > 0 && prev.IsCompleted() {
+			breakEnd := prev.EndTime().Add(breakDuration)
+			if breakEnd.After(to) {
+				breakEnd = to
+			}
+			if breakEnd.After(cursor) {
+				segments = append(segments, Segment{Start: cursor, End: breakEnd, Kind: SegmentBreak, Pomodoro: prev})
+				cursor = breakEnd
+			}
+		}
+
+		if to.After(cursor) {
+			segments = append(segments, Segment{Start: cursor, End: to, Kind: SegmentIdle})
+			cursor = to
+		}
+	}
+
+	for _, p := range h.Pomodoros {
+		if !p.StartTime.Before(end) {
+			break
+		}
+
+		pomodoroEnd := p.EndTime()
+		if pomodoroEnd.After(end) {
+			pomodoroEnd = end
+		}
+		if !pomodoroEnd.After(cursor) {
+			continue
+		}
+
+		fill(p.StartTime)
+		segments = append(segments, Segment{Start: cursor, End: pomodoroEnd, Kind: SegmentPomodoro, Pomodoro: p})
+		cursor = pomodoroEnd
+		prev = p
+	}
+
+	fill(end)
+
+	return segments
+}
+
+// Timeline returns the segments of the `history` file from start up to end
+// like History.Timeline, except that up to the DefaultBreakDuration setting
+// after each completed Pomodoro is a break.
+func (c *Client) Timeline(start, end time.Time) ([]Segment, error) {
+	s, err := c.Settings()
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := c.History()
+	if err != nil {
+		return nil, err
+	}
+
+	return h.timeline(start, end, s.DefaultBreakDuration), nil
+}
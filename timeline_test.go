@@ -0,0 +1,63 @@
+package openpomodoro
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory_Timeline(t *testing.T) {
+	at := func(minutes int) time.Time {
+		return time.Date(2016, 06, 14, 9, 0, 0, 0, time.UTC).Add(time.Duration(minutes) * time.Minute)
+	}
+
+	first := &Pomodoro{StartTime: at(10), Duration: 25 * time.Minute}
+	overlapping := &Pomodoro{StartTime: at(30), Duration: 25 * time.Minute, Cancelled: true}
+	late := &Pomodoro{StartTime: at(110), Duration: 25 * time.Minute}
+	h := &History{Pomodoros: []*Pomodoro{late, overlapping, first}}
+
+	segment := func(start, end int, kind SegmentKind, p *Pomodoro) Segment {
+		return Segment{Start: at(start), End: at(end), Kind: kind, Pomodoro: p}
+	}
+
+	assert.Equal(t, []Segment{
+		segment(0, 10, SegmentIdle, nil),
+		segment(10, 35, SegmentPomodoro, first),
+		segment(35, 55, SegmentPomodoro, overlapping),
+		segment(55, 110, SegmentIdle, nil),
+		segment(110, 120, SegmentPomodoro, late),
+	}, h.Timeline(at(0), at(120)))
+
+	assert.Equal(t, []Segment{
+		segment(20, 35, SegmentPomodoro, first),
+		segment(35, 40, SegmentPomodoro, overlapping),
+	}, h.Timeline(at(20), at(40)))
+
+	assert.Nil(t, h.Timeline(at(0), at(0)))
+}
+
+func TestClient_Timeline(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	start := fakeTime().Add(-time.Hour)
+	require.Nil(t, c.Log(&Pomodoro{}, start, 25*time.Minute))
+	require.Nil(t, c.Log(&Pomodoro{}, start.Add(28*time.Minute), 25*time.Minute))
+
+	segments, err := c.Timeline(start, fakeTime())
+	require.Nil(t, err)
+
+	var kinds []SegmentKind
+	var durations []time.Duration
+	for _, s := range segments {
+		kinds = append(kinds, s.Kind)
+		durations = append(durations, s.End.Sub(s.Start))
+	}
+
+	assert.Equal(t, []SegmentKind{SegmentPomodoro, SegmentBreak, SegmentPomodoro, SegmentBreak, SegmentIdle}, kinds)
+	assert.Equal(t, []time.Duration{
+		25 * time.Minute, 3 * time.Minute, 25 * time.Minute, 5 * time.Minute, 2 * time.Minute,
+	}, durations)
+}
@@ -0,0 +1,116 @@
+package openpomodoro
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+// todayCache is the contents of the `today` file.
+type todayCache struct {
+	Date           string        `json:"date"`
+	HistorySize    int64         `json:"history_size"`
+	HistoryModTime time.Time     `json:"history_mod_time"`
+	Count          int           `json:"count"`
+	Duration       time.Duration `json:"duration"`
+}
+
+// Today returns how many completed Pomodoros in the `history` file started on
+// the day containing now, including one which is running, and their total
+// duration.
+//
+// If TodayCache is enabled, the result is kept in the `today` file, and read
+// from it without scanning the history until the day changes or the `history`
+// file does, including when it is edited by another program.
+func (c *Client) Today(now time.Time) (count int, duration time.Duration, err error) {
+	s, err := c.Settings()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	date := s.Day(now).Format(DateFormat)
+
+	var size int64
+	var modTime time.Time
+	if c.TodayCache {
+		info, err := os.Stat(c.HistoryPath())
+		if err != nil && !os.IsNotExist(err) {
+			return 0, 0, err
+		}
+		if info != nil {
+			size, modTime = info.Size(), info.ModTime()
+		}
+
+		if cache, ok := c.readToday(); ok && cache.Date == date &&
+			cache.HistorySize == size && cache.HistoryModTime.Equal(modTime) {
+			return cache.Count, cache.Duration, nil
+		}
+	}
+
+	h, err := c.History()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, p := range h.Date(s.Day(now)).Pomodoros {
+		if p.IsCompleted() {
+			count++
+			duration += p.Duration
+		}
+	}
+
+	if c.TodayCache {
+		c.writeToday(todayCache{
+			Date:           date,
+			HistorySize:    size,
+			HistoryModTime: modTime,
+			Count:          count,
+			Duration:       duration,
+		})
+	}
+
+	return count, duration, nil
+}
+
+func (c *Client) readToday() (todayCache, bool) {
+	var cache todayCache
+
+	b, err := ioutil.ReadFile(c.TodayPath())
+	if err != nil {
+		return cache, false
+	}
+
+	if err := json.Unmarshal(b, &cache); err != nil {
+		debug("ignoring unreadable today cache: %s", err)
+		return cache, false
+	}
+
+	return cache, true
+}
+
+// writeToday saves the `today` file. It is only a cache, so errors are
+// ignored.
+func (c *Client) writeToday(cache todayCache) {
+	b, err := json.Marshal(cache)
+	if err != nil {
+		return
+	}
+
+	if err := c.writeFile(c.TodayPath(), b); err != nil {
+		debug("unable to write today cache: %s", err)
+	}
+}
+
+// invalidateToday removes the `today` file after the Client changes the
+// `history` file, in case the change is too quick to be noticed from its size
+// and modification time.
+func (c *Client) invalidateToday() {
+	if !c.TodayCache || c.DryRun || c.ReadOnly {
+		return
+	}
+
+	if err := os.Remove(c.TodayPath()); err != nil && !os.IsNotExist(err) {
+		debug("unable to remove today cache: %s", err)
+	}
+}
@@ -0,0 +1,74 @@
+package openpomodoro
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Today(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	require.Nil(t, c.Log(&Pomodoro{}, fakeTime().Add(-2*time.Hour), 25*time.Minute))
+	require.Nil(t, c.Log(&Pomodoro{}, fakeTime().Add(-time.Hour), 20*time.Minute))
+	require.Nil(t, c.Log(&Pomodoro{}, fakeTime().AddDate(0, 0, -1), 25*time.Minute))
+
+	count, duration, err := c.Today(fakeTime())
+	require.Nil(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, 45*time.Minute, duration)
+
+	_, err = os.Stat(c.TodayPath())
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestClient_Today_cache(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())), WithTodayCache())
+	require.Nil(t, err)
+
+	require.Nil(t, c.Log(&Pomodoro{}, fakeTime().Add(-2*time.Hour), 25*time.Minute))
+
+	today := func(now time.Time) int {
+		count, _, err := c.Today(now)
+		require.Nil(t, err)
+		return count
+	}
+
+	assert.Equal(t, 1, today(fakeTime()))
+
+	tamper := func() {
+		b, err := ioutil.ReadFile(c.TodayPath())
+		require.Nil(t, err)
+
+		var cache todayCache
+		require.Nil(t, json.Unmarshal(b, &cache))
+		cache.Count = 99
+
+		b, err = json.Marshal(cache)
+		require.Nil(t, err)
+		require.Nil(t, ioutil.WriteFile(c.TodayPath(), b, FilePerm))
+	}
+
+	tamper()
+	assert.Equal(t, 99, today(fakeTime()))
+	assert.Equal(t, 0, today(fakeTime().AddDate(0, 0, 1)))
+
+	assert.Equal(t, 1, today(fakeTime()))
+	tamper()
+	require.Nil(t, c.Log(&Pomodoro{}, fakeTime().Add(-time.Hour), 25*time.Minute))
+	assert.Equal(t, 2, today(fakeTime()))
+
+	tamper()
+	f, err := os.OpenFile(c.HistoryPath(), os.O_WRONLY|os.O_APPEND, FilePerm)
+	require.Nil(t, err)
+	_, err = f.WriteString("2016-06-14T09:00:00-04:00 duration=25\n")
+	require.Nil(t, err)
+	require.Nil(t, f.Close())
+	assert.Equal(t, 3, today(fakeTime()))
+}
@@ -0,0 +1,81 @@
+package openpomodoro
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+)
+
+// ErrNothingToUndo is returned by Undo when there is no stashed state.
+var ErrNothingToUndo = errors.New("nothing to undo")
+
+// undoState is the contents of the `undo` file.
+type undoState struct {
+	Current string `json:"current"`
+	History string `json:"history"`
+}
+
+// Undo restores the `current` and `history` files to how they were before the
+// last Cancel, CancelWithReason, Clear, or Finish, including when Start
+// cancelled an active Pomodoro. Anything recorded since then is lost. Only one
+// level of undo is kept, so the stashed state is removed once restored.
+func (c *Client) Undo() error {
+	b, err := ioutil.ReadFile(c.UndoPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ErrNothingToUndo
+		}
+		return err
+	}
+
+	var state undoState
+	if err := json.Unmarshal(b, &state); err != nil {
+		return err
+	}
+
+	if err := c.ensureDirectory(); err != nil {
+		return err
+	}
+
+	if err := c.writeFile(c.CurrentPath(), []byte(state.Current)); err != nil {
+		return err
+	}
+
+	if !c.DisableHistory {
+		if err := c.writeFile(c.HistoryPath(), []byte(state.History)); err != nil {
+			return err
+		}
+	}
+
+	return c.removeFile(c.UndoPath())
+}
+
+// stash saves the `current` and `history` files to the `undo` file before
+// they are changed by a destructive action. Nothing is stashed if there is no
+// current Pomodoro, so that the previous stash is kept.
+func (c *Client) stash() error {
+	current, err := c.RawCurrent()
+	if err != nil {
+		return err
+	}
+
+	if bytesAllWhitespace(current) {
+		return nil
+	}
+
+	var history []byte
+	if !c.DisableHistory {
+		history, err = c.RawHistory()
+		if err != nil {
+			return err
+		}
+	}
+
+	b, err := json.Marshal(undoState{Current: string(current), History: string(history)})
+	if err != nil {
+		return err
+	}
+
+	return c.writeFile(c.UndoPath(), b)
+}
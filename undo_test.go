@@ -0,0 +1,49 @@
+package openpomodoro
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Undo(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	assert.Equal(t, ErrNothingToUndo, c.Undo())
+
+	require.Nil(t, c.Log(&Pomodoro{}, fakeTime().Add(-time.Hour), 25*time.Minute))
+	require.Nil(t, c.Start(&Pomodoro{Description: "oops"}))
+	require.Nil(t, c.Cancel())
+
+	assertInactive(true)(t, c, "")
+	assertHistoryLength(1)(t, c, "")
+
+	require.Nil(t, c.Undo())
+
+	p, err := c.Pomodoro()
+	require.Nil(t, err)
+	assert.Equal(t, "oops", p.Description)
+	assertHistoryLength(2)(t, c, "")
+
+	assert.Equal(t, ErrNothingToUndo, c.Undo())
+}
+
+func Test_Undo_finish(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	require.Nil(t, c.Start(&Pomodoro{StartTime: fakeTime().Add(-10 * time.Minute)}))
+	require.Nil(t, c.Finish())
+	require.Nil(t, c.Clear())
+
+	require.Nil(t, c.Undo())
+
+	assertActive(true)(t, c, "")
+
+	h, err := c.History()
+	require.Nil(t, err)
+	assert.Equal(t, 25*time.Minute, h.Latest().Duration)
+}
@@ -0,0 +1,52 @@
+package openpomodoro
+
+import (
+	"context"
+	"time"
+)
+
+// waitInterval is the longest WaitUntilDone sleeps before re-reading the
+// `current` file.
+const waitInterval = time.Second
+
+var afterFunc = time.After
+
+// WaitUntilDone blocks until the current Pomodoro is no longer active, and then
+// returns it. The `current` file is re-read periodically, so a Pomodoro which
+// is restarted, finished, or cancelled in the meantime is noticed. It returns
+// immediately if no Pomodoro is active, and returns the context's error if it
+// is cancelled first. If the Pomodoro became done while waiting, the Notifier
+// is sent EventDone and EventBreak.
+func (c *Client) WaitUntilDone(ctx context.Context) (*Pomodoro, error) {
+	waited := false
+
+	for {
+		p, err := c.Pomodoro()
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.IsActive() {
+			if waited && p.IsDone() {
+				c.notify(EventDone, p)
+				c.notify(EventBreak, p)
+			}
+			return p, nil
+		}
+
+		d := p.Remaining()
+		if d > waitInterval {
+			d = waitInterval
+		}
+		if d <= 0 {
+			d = time.Millisecond
+		}
+
+		select {
+		case <-ctx.Done():
+			return p, ctx.Err()
+		case <-afterFunc(d):
+			waited = true
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package openpomodoro
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WaitUntilDone(t *testing.T) {
+	defer func() { afterFunc = time.After }()
+
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	n := &recordingNotifier{}
+	c.Notifier = n
+
+	p, err := c.WaitUntilDone(context.Background())
+	require.Nil(t, err)
+	assert.True(t, p.IsInactive())
+
+	require.Nil(t, c.Start(&Pomodoro{Description: "waiting"}))
+
+	var slept time.Duration
+	afterFunc = func(d time.Duration) <-chan time.Time {
+		slept += d
+		timeTravel(d)(t, c, "")
+		ch := make(chan time.Time, 1)
+		ch <- c.Clock.Now()
+		return ch
+	}
+
+	p, err = c.WaitUntilDone(context.Background())
+	require.Nil(t, err)
+	assert.Equal(t, "waiting", p.Description)
+	assert.True(t, p.IsDone())
+	assert.Equal(t, 25*time.Minute+time.Millisecond, slept)
+	assert.Equal(t, []string{EventStart, EventDone, EventBreak}, n.events)
+}
+
+func Test_WaitUntilDone_cancelled(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	require.Nil(t, c.Start(&Pomodoro{}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = c.WaitUntilDone(ctx)
+	assert.Equal(t, context.Canceled, err)
+}
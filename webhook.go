@@ -0,0 +1,66 @@
+package openpomodoro
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DefaultWebhookTimeout is used by a WebhookNotifier without a Client or
+// Timeout.
+const DefaultWebhookTimeout = 10 * time.Second
+
+// WebhookNotifier is a Notifier which POSTs each event and Pomodoro as JSON
+// to a URL.
+type WebhookNotifier struct {
+	URL string
+
+	// Client is the HTTP client used to make requests. If nil, a client with
+	// Timeout is used.
+	Client *http.Client
+
+	// Timeout is the timeout for requests when Client is nil. If zero,
+	// DefaultWebhookTimeout is used.
+	Timeout time.Duration
+}
+
+type webhookPayload struct {
+	Event    string    `json:"event"`
+	Pomodoro *Pomodoro `json:"pomodoro"`
+}
+
+// Notify implements Notifier. A response with a non-2xx status is returned as
+// an error.
+func (n *WebhookNotifier) Notify(event string, p *Pomodoro) error {
+	b, err := json.Marshal(webhookPayload{Event: event, Pomodoro: p})
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.client().Post(n.URL, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+
+	return nil
+}
+
+func (n *WebhookNotifier) client() *http.Client {
+	if n.Client != nil {
+		return n.Client
+	}
+
+	timeout := n.Timeout
+	if timeout == 0 {
+		timeout = DefaultWebhookTimeout
+	}
+
+	return &http.Client{Timeout: timeout}
+}
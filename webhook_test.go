@@ -0,0 +1,53 @@
+package openpomodoro
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WebhookNotifier(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+	}))
+	defer server.Close()
+
+	n := &WebhookNotifier{URL: server.URL}
+	p := &Pomodoro{
+		StartTime: time.Date(2016, 06, 14, 12, 0, 0, 0, time.UTC),
+		Duration:  25 * time.Minute,
+	}
+
+	require.Nil(t, n.Notify(EventStart, p))
+	assert.Equal(t,
+		`{"event":"start","pomodoro":{"start_time":"2016-06-14T12:00:00Z","description":"","duration":25,"end_time":"2016-06-14T12:25:00Z","tags":null}}`,
+		body)
+}
+
+func Test_WebhookNotifier_errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(100 * time.Millisecond)
+			return
+		}
+		http.Error(w, "nope", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	n := &WebhookNotifier{URL: server.URL}
+	err := n.Notify(EventStart, &Pomodoro{})
+	require.NotNil(t, err)
+	assert.Equal(t, "webhook returned 500 Internal Server Error", err.Error())
+
+	n = &WebhookNotifier{URL: server.URL + "/slow", Timeout: 10 * time.Millisecond}
+	assert.NotNil(t, n.Notify(EventStart, &Pomodoro{}))
+}
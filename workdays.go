@@ -0,0 +1,160 @@
+package openpomodoro
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Weekdays are a set of days of the week.
+//
+// In text, they are written as comma-separated names, which may be
+// abbreviated to three letters, such as "Mon,Tue,Wed,Thu,Fri".
+type Weekdays []time.Weekday
+
+// Contains returns whether or not the day is one of the weekdays.
+func (ws Weekdays) Contains(day time.Weekday) bool {
+	for _, w := range ws {
+		if w == day {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (ws Weekdays) MarshalText() ([]byte, error) {
+	names := make([]string, len(ws))
+	for i, w := range ws {
+		names[i] = w.String()[:3]
+	}
+
+	return []byte(strings.Join(names, ",")), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (ws *Weekdays) UnmarshalText(b []byte) error {
+	days := Weekdays{}
+
+	for _, name := range strings.Split(string(b), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		day, err := parseWeekday(name)
+		if err != nil {
+			return err
+		}
+		days = append(days, day)
+	}
+
+	*ws = days
+	return nil
+}
+
+func parseWeekday(name string) (time.Weekday, error) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		full := day.String()
+		if strings.EqualFold(name, full) || strings.EqualFold(name, full[:3]) {
+			return day, nil
+		}
+	}
+
+	return 0, fmt.Errorf("invalid weekday %q", name)
+}
+
+// validateHolidays returns an error if any of the holidays is not a date in
+// DateFormat.
+func validateHolidays(holidays []string) error {
+	for _, holiday := range holidays {
+		if _, err := time.Parse(DateFormat, holiday); err != nil {
+			return fmt.Errorf("invalid holiday %q", holiday)
+		}
+	}
+
+	return nil
+}
+
+// IsWorkDay returns whether or not the date is one of the WorkDays and is not
+// one of the Holidays. Every day is a work day if WorkDays is empty.
+func (s *Settings) IsWorkDay(date time.Time) bool {
+	if len(s.WorkDays) > 0 && !s.WorkDays.Contains(date.Weekday()) {
+		return false
+	}
+
+	for _, holiday := range s.Holidays {
+		if date.Format(DateFormat) == holiday {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Streak returns how many days in a row, up to the day containing now, had at
+// least goal Pomodoros completed, with days in now's location beginning at the
+// DayStartOffset. The day containing now only breaks the streak once it is
+// over. Days for which isWorkDay returns false do not break the streak either,
+// but still count towards it if the goal was met. A nil isWorkDay treats every
+// day as a work day. It returns 0 if no goal is set.
+func (h *History) Streak(goal int, now time.Time, isWorkDay func(date time.Time) bool) int {
+	if goal <= 0 || len(h.Pomodoros) == 0 {
+		return 0
+	}
+
+	loc := now.Location()
+	date := func(t time.Time) time.Time {
+		y, m, d := t.In(loc).Add(-h.DayStartOffset).Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, loc)
+	}
+
+	first := h.Pomodoros[0].StartTime
+	for _, p := range h.Pomodoros {
+		if p.StartTime.Before(first) {
+			first = p.StartTime
+		}
+	}
+
+	index := h.IndexByDate(loc)
+	today := date(now)
+	streak := 0
+
+	for day := today; !day.Before(date(first)); day = day.AddDate(0, 0, -1) {
+		completed := 0
+		for _, p := range index.Day(day).Pomodoros {
+			if p.IsCompleted() && !p.EndTime().After(now) {
+				completed++
+			}
+		}
+
+		switch {
+		case completed >= goal:
+			streak++
+		case day.Equal(today):
+		case isWorkDay != nil && !isWorkDay(day):
+		default:
+			return streak
+		}
+	}
+
+	return streak
+}
+
+// Streak returns how many days in a row, up to the day containing now, the
+// daily goal was met, where days which are not work days do not break the
+// streak. It returns 0 if no goal is set.
+func (c *Client) Streak(now time.Time) (int, error) {
+	s, err := c.Settings()
+	if err != nil {
+		return 0, err
+	}
+
+	h, err := c.History()
+	if err != nil {
+		return 0, err
+	}
+
+	return h.Streak(s.DailyGoal, now, s.IsWorkDay), nil
+}
@@ -0,0 +1,97 @@
+package openpomodoro
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeekdays_Text(t *testing.T) {
+	var ws Weekdays
+	require.Nil(t, ws.UnmarshalText([]byte("sat, Sunday,MON")))
+	assert.Equal(t, Weekdays{time.Saturday, time.Sunday, time.Monday}, ws)
+	assert.True(t, ws.Contains(time.Sunday))
+	assert.False(t, ws.Contains(time.Tuesday))
+
+	b, err := ws.MarshalText()
+	require.Nil(t, err)
+	assert.Equal(t, "Sat,Sun,Mon", string(b))
+
+	assert.NotNil(t, ws.UnmarshalText([]byte("Mon,Funday")))
+}
+
+func TestSettings_IsWorkDay(t *testing.T) {
+	s := &Settings{
+		WorkDays: Weekdays{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+		Holidays: []string{"2016-06-13"},
+	}
+
+	assert.True(t, s.IsWorkDay(time.Date(2016, 06, 14, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, s.IsWorkDay(time.Date(2016, 06, 13, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, s.IsWorkDay(time.Date(2016, 06, 12, 0, 0, 0, 0, time.UTC)))
+
+	assert.True(t, (&Settings{}).IsWorkDay(time.Date(2016, 06, 12, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestHistory_Streak(t *testing.T) {
+	at := func(day int) *Pomodoro {
+		return &Pomodoro{StartTime: time.Date(2016, 06, day, 10, 0, 0, 0, time.UTC), Duration: 25 * time.Minute}
+	}
+	weekdays := &Settings{WorkDays: Weekdays{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}}
+	tuesday := time.Date(2016, 06, 14, 18, 0, 0, 0, time.UTC)
+
+	// Thursday and Friday, then Monday and Tuesday after a weekend off.
+	h := &History{Pomodoros: []*Pomodoro{at(9), at(10), at(13), at(14)}}
+
+	assert.Equal(t, 2, h.Streak(1, tuesday, nil))
+	assert.Equal(t, 4, h.Streak(1, tuesday, weekdays.IsWorkDay))
+	assert.Equal(t, 0, h.Streak(2, tuesday, weekdays.IsWorkDay))
+	assert.Equal(t, 0, h.Streak(0, tuesday, weekdays.IsWorkDay))
+	assert.Equal(t, 0, empty.Streak(1, tuesday, nil))
+
+	// Wednesday is not over yet, so it does not break the streak.
+	assert.Equal(t, 4, h.Streak(1, tuesday.Add(16*time.Hour), weekdays.IsWorkDay))
+	assert.Equal(t, 0, h.Streak(1, tuesday.Add(40*time.Hour), weekdays.IsWorkDay))
+
+	// A Pomodoro on the weekend still counts.
+	h.Pomodoros = append(h.Pomodoros, at(11))
+	assert.Equal(t, 5, h.Streak(1, tuesday, weekdays.IsWorkDay))
+
+	// A holiday does not break the streak.
+	h = &History{Pomodoros: []*Pomodoro{at(10), at(14)}}
+	weekdays.Holidays = []string{"2016-06-13"}
+	assert.Equal(t, 2, h.Streak(1, tuesday, weekdays.IsWorkDay))
+}
+
+func TestClient_Streak(t *testing.T) {
+	c, err := NewClient(fixture(""), WithClock(NewFakeClock(fakeTime())))
+	require.Nil(t, err)
+
+	require.Nil(t, c.ensureDirectory())
+	require.Nil(t, ioutil.WriteFile(c.SettingsPath(), []byte("daily_goal=1 work_days=mon,tue,wed,thu,fri"), FilePerm))
+
+	friday := fakeTime().AddDate(0, 0, -4)
+	require.Nil(t, c.Log(&Pomodoro{}, friday, 25*time.Minute))
+	require.Nil(t, c.Log(&Pomodoro{}, fakeTime().AddDate(0, 0, -1), 25*time.Minute))
+
+	streak, err := c.Streak(fakeTime())
+	require.Nil(t, err)
+	assert.Equal(t, 2, streak)
+
+	saturday := friday.AddDate(0, 0, 1)
+	pomodoros, _, err := c.RemainingToGoal(saturday)
+	require.Nil(t, err)
+	assert.Equal(t, 0, pomodoros)
+
+	onTrack, expected, _, err := c.GoalPace(saturday)
+	require.Nil(t, err)
+	assert.True(t, onTrack)
+	assert.Equal(t, 0, expected)
+
+	pomodoros, _, err = c.RemainingToGoal(fakeTime())
+	require.Nil(t, err)
+	assert.Equal(t, 1, pomodoros)
+}